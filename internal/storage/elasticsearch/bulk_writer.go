@@ -0,0 +1,387 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// BulkItem is a single document queued through a BulkWriter. Index/Type/OpType
+// mirror the arguments callers previously passed to client().Index()...Add(),
+// so existing write paths only need to change how the request is dispatched,
+// not how it is built.
+type BulkItem struct {
+	Index  string
+	Type   string
+	OpType string
+	Body   any
+}
+
+// BulkItemError pairs a BulkItem the backend rejected with the reason, and
+// reports whether the failure is safe to retry (429/503, network errors) or
+// permanent (mapping errors, 4xx other than 429).
+type BulkItemError struct {
+	Item      BulkItem
+	Err       error
+	Retryable bool
+}
+
+// BulkFlushFunc sends a batch of items to the backend. It returns the items
+// the backend rejected (if any); an item absent from the result succeeded.
+// A non-nil error indicates the whole batch failed at the transport level
+// (e.g. the request itself could not be sent), in which case every item in
+// the batch is treated as retryable.
+type BulkFlushFunc func(ctx context.Context, items []BulkItem) ([]BulkItemError, error)
+
+// BulkWriterOptions configures the batching, flushing and retry behavior of a BulkWriter.
+type BulkWriterOptions struct {
+	// BulkActions is the number of queued items that triggers an immediate flush.
+	BulkActions int
+	// BulkSize is the approximate cumulative body size in bytes that triggers
+	// an immediate flush. Zero disables the size-based trigger. Only bodies that
+	// implement Len() int are measured; any other body counts as zero bytes.
+	BulkSize int
+	// FlushInterval forces a flush of whatever is queued even below BulkActions/BulkSize.
+	FlushInterval time.Duration
+	// Workers is the number of goroutines draining flush batches concurrently.
+	Workers int
+	// MaxElapsedTime bounds how long a retryable failure is retried before
+	// giving up and reporting it to OnFailure. Zero (the default) retries forever;
+	// callers that Await Add's result channel should set this so a persistent
+	// outage can't block them indefinitely.
+	MaxElapsedTime time.Duration
+	// OnFailure, if set, is called with items that exhausted retries or
+	// failed permanently, so operators can route them to a DLQ.
+	OnFailure func(items []BulkItem, err error)
+}
+
+const (
+	defaultBulkActions   = 1000
+	defaultFlushInterval = time.Second
+	defaultWorkers       = 1
+
+	backoffBase = 100 * time.Millisecond
+	backoffCap  = 30 * time.Second
+)
+
+func (o *BulkWriterOptions) applyDefaults() {
+	if o.BulkActions <= 0 {
+		o.BulkActions = defaultBulkActions
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = defaultFlushInterval
+	}
+	if o.Workers <= 0 {
+		o.Workers = defaultWorkers
+	}
+	// MaxElapsedTime <= 0 is left as-is: it means "retry forever", handled in
+	// flushWithRetry by leaving the deadline zero rather than coercing it to
+	// some finite default.
+}
+
+// trackedItem pairs a queued BulkItem with the (optional) channel its final
+// outcome is reported on, so Add can give callers real error observability
+// without changing the BulkItem/BulkFlushFunc contract every backend already
+// implements.
+type trackedItem struct {
+	item   BulkItem
+	result chan<- error
+}
+
+// trackedFailure pairs a trackedItem with the error it failed with.
+type trackedFailure struct {
+	item trackedItem
+	err  error
+}
+
+// BulkWriter batches documents written via Add and flushes them through a
+// BulkFlushFunc on a timer or once BulkActions/BulkSize is reached, retrying
+// retryable failures with exponential backoff and full jitter.
+type BulkWriter struct {
+	opts  BulkWriterOptions
+	flush BulkFlushFunc
+
+	mu          sync.Mutex
+	pending     []trackedItem
+	pendingSize int
+
+	flushCh chan []trackedItem
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewBulkWriter creates a BulkWriter that dispatches batched items to flush.
+func NewBulkWriter(opts BulkWriterOptions, flush BulkFlushFunc) *BulkWriter {
+	opts.applyDefaults()
+	w := &BulkWriter{
+		opts:    opts,
+		flush:   flush,
+		flushCh: make(chan []trackedItem, opts.Workers),
+		done:    make(chan struct{}),
+	}
+	w.wg.Add(opts.Workers + 1)
+	for i := 0; i < opts.Workers; i++ {
+		go w.worker()
+	}
+	go w.flushOnInterval()
+	return w
+}
+
+// Add queues item for the next flush, triggering one immediately once
+// BulkActions or BulkSize is reached. It returns a channel that receives
+// exactly one value once the item's outcome is known: nil on success, or the
+// error it failed with (permanently, or after MaxElapsedTime of retries).
+// The channel is always written to and closed, so a caller that discards it
+// leaks nothing; callers that want a synchronous, error-observing write can
+// pass it to Await instead of treating Add as fire-and-forget.
+func (w *BulkWriter) Add(item BulkItem) <-chan error {
+	result := make(chan error, 1)
+	w.mu.Lock()
+	w.pending = append(w.pending, trackedItem{item: item, result: result})
+	w.pendingSize += approxSize(item)
+	full := len(w.pending) >= w.opts.BulkActions || (w.opts.BulkSize > 0 && w.pendingSize >= w.opts.BulkSize)
+	var batch []trackedItem
+	if full {
+		batch = w.takePendingLocked()
+	}
+	w.mu.Unlock()
+	if batch != nil {
+		w.sendBatch(batch)
+	}
+	return result
+}
+
+// sendBatch hands batch to a worker, racing the send against Close so a
+// producer can never be left blocked on flushCh once shutdown has started
+// (the same select on done that AsyncWriterDecorator.enqueueBlocking uses,
+// though that one drops the span instead). If Close wins the race, batch is
+// flushed synchronously right here. flushCh itself is never closed, so there
+// is nothing for a losing send to panic against.
+func (w *BulkWriter) sendBatch(batch []trackedItem) {
+	select {
+	case w.flushCh <- batch:
+	case <-w.done:
+		w.flushWithRetry(batch)
+	}
+}
+
+// Await blocks until every result channel returned by Add has resolved,
+// joining any non-nil errors. Use it to turn a batch of Add calls back into a
+// single synchronous, error-observing write.
+func Await(results ...<-chan error) error {
+	var errs []error
+	for _, r := range results {
+		if err := <-r; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// takePendingLocked must be called with w.mu held.
+func (w *BulkWriter) takePendingLocked() []trackedItem {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	batch := w.pending
+	w.pending = nil
+	w.pendingSize = 0
+	return batch
+}
+
+func (w *BulkWriter) flushOnInterval() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.mu.Lock()
+			batch := w.takePendingLocked()
+			w.mu.Unlock()
+			if batch != nil {
+				w.sendBatch(batch)
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// worker drains flushCh until told to stop. It never ranges over flushCh,
+// since flushCh is never closed (see sendBatch); once done fires it drains
+// whatever is already buffered, without blocking for more, then exits.
+func (w *BulkWriter) worker() {
+	defer w.wg.Done()
+	for {
+		select {
+		case batch := <-w.flushCh:
+			w.flushWithRetry(batch)
+		case <-w.done:
+			w.drain()
+			return
+		}
+	}
+}
+
+// drain flushes whatever remains buffered in flushCh, without blocking for more to arrive.
+func (w *BulkWriter) drain() {
+	for {
+		select {
+		case batch := <-w.flushCh:
+			w.flushWithRetry(batch)
+		default:
+			return
+		}
+	}
+}
+
+// flushWithRetry sends batch through flush, retrying retryable failures with
+// exponential backoff and full jitter until MaxElapsedTime elapses. A zero
+// MaxElapsedTime leaves deadline zero, i.e. retries forever. Every item's
+// result channel is resolved exactly once, as soon as its outcome (success,
+// permanent failure, or exhausted retries) is known.
+func (w *BulkWriter) flushWithRetry(batch []trackedItem) {
+	ctx := context.Background()
+	var deadline time.Time
+	if w.opts.MaxElapsedTime > 0 {
+		deadline = time.Now().Add(w.opts.MaxElapsedTime)
+	}
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		items := itemsOf(batch)
+		failed, err := w.flush(ctx, items)
+		lastErr = err
+		retryable, permanent, succeeded := partitionFailures(batch, failed, err)
+		resolveAll(succeeded, nil)
+		w.reportPermanent(permanent)
+		if len(retryable) == 0 {
+			return
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			giveUpErr := fmt.Errorf("giving up after %s: %w", w.opts.MaxElapsedTime, lastErr)
+			if w.opts.OnFailure != nil {
+				w.opts.OnFailure(itemsOf(retryable), giveUpErr)
+			}
+			resolveAll(retryable, giveUpErr)
+			return
+		}
+		time.Sleep(fullJitterBackoff(attempt))
+		batch = retryable
+	}
+}
+
+// partitionFailures splits a flush outcome into items worth retrying, items
+// that failed permanently, and items that succeeded. A non-nil
+// transport-level err with no itemized failures means the whole batch is
+// retryable. Matching failed items back to the batch is by value (safe as
+// long as BulkItem.Body holds a comparable value, which every backend in this
+// tree does by putting a pointer there), consumed FIFO per distinct value so
+// that if a batch happens to contain several equal-valued items and only
+// some of them fail, exactly that many are classified as failed rather than
+// every occurrence inheriting the same verdict.
+func partitionFailures(batch []trackedItem, failed []BulkItemError, err error) (retryable []trackedItem, permanent []trackedFailure, succeeded []trackedItem) {
+	if err != nil && len(failed) == 0 {
+		return batch, nil, nil
+	}
+	pending := make(map[BulkItem][]BulkItemError, len(failed))
+	for _, f := range failed {
+		pending[f.Item] = append(pending[f.Item], f)
+	}
+	for _, t := range batch {
+		queue := pending[t.item]
+		if len(queue) == 0 {
+			succeeded = append(succeeded, t)
+			continue
+		}
+		pending[t.item] = queue[1:]
+		f := queue[0]
+		if f.Retryable {
+			retryable = append(retryable, t)
+		} else {
+			permanent = append(permanent, trackedFailure{item: t, err: f.Err})
+		}
+	}
+	return retryable, permanent, succeeded
+}
+
+func (w *BulkWriter) reportPermanent(permanent []trackedFailure) {
+	if len(permanent) == 0 {
+		return
+	}
+	items := make([]BulkItem, len(permanent))
+	errs := make([]error, len(permanent))
+	for i, p := range permanent {
+		items[i] = p.item.item
+		errs[i] = p.err
+		resolveOne(p.item, p.err)
+	}
+	if w.opts.OnFailure != nil {
+		w.opts.OnFailure(items, errors.Join(errs...))
+	}
+}
+
+// resolveAll sends err to every item's result channel, if any, and closes it.
+func resolveAll(items []trackedItem, err error) {
+	for _, t := range items {
+		resolveOne(t, err)
+	}
+}
+
+func resolveOne(t trackedItem, err error) {
+	if t.result == nil {
+		return
+	}
+	t.result <- err
+	close(t.result)
+}
+
+func itemsOf(batch []trackedItem) []BulkItem {
+	items := make([]BulkItem, len(batch))
+	for i, t := range batch {
+		items[i] = t.item
+	}
+	return items
+}
+
+// fullJitterBackoff implements sleep = rand(0, min(cap, base*2^attempt)).
+func fullJitterBackoff(attempt int) time.Duration {
+	maxDelay := float64(backoffBase) * math.Pow(2, float64(attempt))
+	if maxDelay <= 0 || maxDelay > float64(backoffCap) {
+		maxDelay = float64(backoffCap)
+	}
+	return time.Duration(rand.Int63n(int64(maxDelay) + 1)) //nolint:gosec
+}
+
+func approxSize(item BulkItem) int {
+	type sizer interface{ Len() int }
+	if s, ok := item.Body.(sizer); ok {
+		return s.Len()
+	}
+	return 0
+}
+
+// Close flushes any queued items one last time and stops background workers.
+// flushCh is never closed: a concurrent Add or flushOnInterval tick can still
+// be blocked trying to send on it when Close runs, and closing a channel a
+// producer might send on next is exactly the panic this is built to avoid.
+// Shutdown is instead driven entirely by closing done, which every producer
+// and worker selects on.
+func (w *BulkWriter) Close() error {
+	close(w.done)
+	w.mu.Lock()
+	batch := w.takePendingLocked()
+	w.mu.Unlock()
+	if batch != nil {
+		w.flushWithRetry(batch)
+	}
+	w.wg.Wait()
+	return nil
+}
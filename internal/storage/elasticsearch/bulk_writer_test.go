@@ -0,0 +1,281 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkWriter_FlushesSingleBatchUnderBulkActions(t *testing.T) {
+	var flushes int32
+	var mu sync.Mutex
+	var received []BulkItem
+	done := make(chan struct{})
+
+	w := NewBulkWriter(BulkWriterOptions{
+		BulkActions:   100,
+		FlushInterval: 10 * time.Millisecond,
+		Workers:       1,
+	}, func(_ context.Context, items []BulkItem) ([]BulkItemError, error) {
+		atomic.AddInt32(&flushes, 1)
+		mu.Lock()
+		received = append(received, items...)
+		mu.Unlock()
+		close(done)
+		return nil, nil
+	})
+
+	for i := 0; i < 10; i++ {
+		w.Add(BulkItem{Index: "jaeger-sampling-000001"})
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for flush")
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&flushes))
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, received, 10)
+}
+
+func TestBulkWriter_RetriesOnRetryableFailureWithBackoff(t *testing.T) {
+	var attempts int32
+	item := BulkItem{Index: "jaeger-sampling-000001"}
+	done := make(chan struct{})
+
+	w := NewBulkWriter(BulkWriterOptions{
+		BulkActions:    1,
+		FlushInterval:  time.Hour,
+		Workers:        1,
+		MaxElapsedTime: time.Second,
+	}, func(_ context.Context, items []BulkItem) ([]BulkItemError, error) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n < 3 {
+			return []BulkItemError{{Item: items[0], Err: errors.New("429 Too Many Requests"), Retryable: true}}, nil
+		}
+		close(done)
+		return nil, nil
+	})
+
+	w.Add(item)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for eventual success")
+	}
+	require.NoError(t, w.Close())
+
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&attempts), int32(3))
+}
+
+func TestBulkWriter_GivesUpSilentlyWithoutOnFailure(t *testing.T) {
+	item := BulkItem{Index: "jaeger-sampling-000001"}
+	var attempts int32
+
+	w := NewBulkWriter(BulkWriterOptions{
+		BulkActions:    1,
+		FlushInterval:  time.Hour,
+		Workers:        1,
+		MaxElapsedTime: 10 * time.Millisecond,
+		// OnFailure intentionally left nil: it is documented as optional, and
+		// exhausting retries with no OnFailure set must not panic.
+	}, func(_ context.Context, items []BulkItem) ([]BulkItemError, error) {
+		atomic.AddInt32(&attempts, 1)
+		return []BulkItemError{{Item: items[0], Err: errors.New("429 Too Many Requests"), Retryable: true}}, nil
+	})
+
+	require.NotPanics(t, func() {
+		w.Add(item)
+		require.NoError(t, w.Close())
+	})
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&attempts), int32(1))
+}
+
+func TestBulkWriter_ZeroMaxElapsedTimeRetriesUntilSuccess(t *testing.T) {
+	var attempts int32
+	item := BulkItem{Index: "jaeger-sampling-000001"}
+	done := make(chan struct{})
+
+	w := NewBulkWriter(BulkWriterOptions{
+		BulkActions:   1,
+		FlushInterval: time.Hour,
+		Workers:       1,
+		// MaxElapsedTime left at zero: documented as "retries forever".
+	}, func(_ context.Context, items []BulkItem) ([]BulkItemError, error) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n < 5 {
+			return []BulkItemError{{Item: items[0], Err: errors.New("429 Too Many Requests"), Retryable: true}}, nil
+		}
+		close(done)
+		return nil, nil
+	})
+
+	w.Add(item)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for eventual success")
+	}
+	require.NoError(t, w.Close())
+
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&attempts), int32(5))
+}
+
+func TestBulkWriter_PermanentFailurePropagatesToOnFailure(t *testing.T) {
+	item := BulkItem{Index: "jaeger-sampling-000001"}
+	var gotItems []BulkItem
+	var gotErr error
+	done := make(chan struct{})
+
+	w := NewBulkWriter(BulkWriterOptions{
+		BulkActions:   1,
+		FlushInterval: time.Hour,
+		Workers:       1,
+		OnFailure: func(items []BulkItem, err error) {
+			gotItems = items
+			gotErr = err
+			close(done)
+		},
+	}, func(_ context.Context, items []BulkItem) ([]BulkItemError, error) {
+		return []BulkItemError{{Item: items[0], Err: errors.New("mapper_parsing_exception"), Retryable: false}}, nil
+	})
+
+	w.Add(item)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for OnFailure")
+	}
+	require.NoError(t, w.Close())
+
+	require.Len(t, gotItems, 1)
+	assert.Equal(t, item, gotItems[0])
+	assert.ErrorContains(t, gotErr, "mapper_parsing_exception")
+}
+
+func TestBulkWriter_AddResultChannelResolvesPerItem(t *testing.T) {
+	ok := BulkItem{Index: "jaeger-sampling-000001", Body: "ok"}
+	bad := BulkItem{Index: "jaeger-sampling-000001", Body: "bad"}
+
+	w := NewBulkWriter(BulkWriterOptions{
+		BulkActions:   2,
+		FlushInterval: time.Hour,
+		Workers:       1,
+	}, func(_ context.Context, items []BulkItem) ([]BulkItemError, error) {
+		return []BulkItemError{{Item: bad, Err: errors.New("mapper_parsing_exception"), Retryable: false}}, nil
+	})
+
+	okResult := w.Add(ok)
+	badResult := w.Add(bad)
+
+	assert.NoError(t, <-okResult)
+	assert.ErrorContains(t, <-badResult, "mapper_parsing_exception")
+	require.NoError(t, w.Close())
+}
+
+// TestBulkWriter_CloseDoesNotPanicOnBlockedSend reproduces a worker busy
+// flushing one batch while flushCh's single slot is already occupied by
+// another, so a concurrent Add has nowhere to send and blocks on flushCh
+// right as Close runs. Close must not close flushCh out from under that
+// blocked send.
+func TestBulkWriter_CloseDoesNotPanicOnBlockedSend(t *testing.T) {
+	block := make(chan struct{})
+	started := make(chan struct{}, 1)
+
+	w := NewBulkWriter(BulkWriterOptions{
+		BulkActions:   1,
+		FlushInterval: time.Hour,
+		Workers:       1,
+	}, func(_ context.Context, items []BulkItem) ([]BulkItemError, error) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-block
+		return nil, nil
+	})
+
+	// The worker picks up item 1 and blocks in flush; item 2 fills flushCh's
+	// single slot; a third Add has nowhere to go and blocks on the send, so
+	// it must run on its own goroutine.
+	w.Add(BulkItem{Index: "jaeger-sampling-000001", Body: "1"})
+	<-started
+	w.Add(BulkItem{Index: "jaeger-sampling-000001", Body: "2"})
+
+	thirdResult := make(chan (<-chan error), 1)
+	go func() {
+		thirdResult <- w.Add(BulkItem{Index: "jaeger-sampling-000001", Body: "3"})
+	}()
+
+	closeDone := make(chan error, 1)
+	require.NotPanics(t, func() {
+		go func() {
+			closeDone <- w.Close()
+		}()
+	})
+
+	// Unblock the worker so flushing (including the synchronous fallback
+	// flush Close's winning side of the race performs for item 3) can complete.
+	close(block)
+
+	select {
+	case err := <-closeDone:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Close")
+	}
+	select {
+	case result := <-thirdResult:
+		require.NoError(t, <-result)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the blocked Add to resolve")
+	}
+}
+
+// TestBulkWriter_EqualItemsResolveIndependently guards against a batch with
+// several equal-valued BulkItems (a plausible real case: a flush of several
+// identical "heartbeat" documents) collapsing onto one verdict: only as many
+// of them should be treated as failed as flush actually reported.
+func TestBulkWriter_EqualItemsResolveIndependently(t *testing.T) {
+	item := BulkItem{Index: "jaeger-sampling-000001"}
+
+	w := NewBulkWriter(BulkWriterOptions{
+		BulkActions:   3,
+		FlushInterval: time.Hour,
+		Workers:       1,
+	}, func(_ context.Context, items []BulkItem) ([]BulkItemError, error) {
+		// Only the first of the three equal items failed.
+		return []BulkItemError{{Item: items[0], Err: errors.New("mapper_parsing_exception"), Retryable: false}}, nil
+	})
+
+	results := []<-chan error{w.Add(item), w.Add(item), w.Add(item)}
+
+	var failed, succeeded int
+	for _, r := range results {
+		if err := <-r; err != nil {
+			failed++
+		} else {
+			succeeded++
+		}
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, 2, succeeded)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// ESIndexResolver is the concrete IndexResolver backed by a real Elasticsearch
+// cluster. It resolves a pattern in two calls: GET _resolve/index/{pattern}
+// to enumerate the concrete indices and data-stream backing indices behind
+// it, then GET {names}/_settings to read each one's creation date. The
+// _resolve/index response itself carries no settings, so the second call
+// can't be avoided.
+type ESIndexResolver struct {
+	client func() *elastic.Client
+}
+
+// NewESIndexResolver returns an IndexResolver that calls the real
+// Elasticsearch _resolve/index API through client.
+func NewESIndexResolver(client func() *elastic.Client) *ESIndexResolver {
+	return &ESIndexResolver{client: client}
+}
+
+type resolveIndexResponse struct {
+	Indices []struct {
+		Name string `json:"name"`
+	} `json:"indices"`
+	DataStreams []struct {
+		BackingIndices []string `json:"backing_indices"`
+	} `json:"data_streams"`
+}
+
+// settingsResponse maps index name -> {settings: {index: {creation_date: "...millis..."}}}
+type settingsResponse map[string]struct {
+	Settings struct {
+		Index struct {
+			CreationDate string `json:"creation_date"`
+		} `json:"index"`
+	} `json:"settings"`
+}
+
+func (r *ESIndexResolver) ResolveIndices(ctx context.Context, pattern string) ([]ResolvedIndex, error) {
+	names, err := r.resolveNames(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return r.resolveCreationDates(ctx, names)
+}
+
+func (r *ESIndexResolver) resolveNames(ctx context.Context, pattern string) ([]string, error) {
+	resp, err := r.client().PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method:       http.MethodGet,
+		Path:         "/_resolve/index/" + pattern,
+		IgnoreErrors: []int{http.StatusNotFound},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("_resolve/index request failed: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrResolveUnavailable
+	}
+	var parsed resolveIndexResponse
+	if err := json.Unmarshal(resp.Body, &parsed); err != nil {
+		return nil, fmt.Errorf("unmarshalling _resolve/index response failed: %w", err)
+	}
+	var names []string
+	for _, idx := range parsed.Indices {
+		names = append(names, idx.Name)
+	}
+	for _, ds := range parsed.DataStreams {
+		names = append(names, ds.BackingIndices...)
+	}
+	return names, nil
+}
+
+func (r *ESIndexResolver) resolveCreationDates(ctx context.Context, names []string) ([]ResolvedIndex, error) {
+	resp, err := r.client().PerformRequest(ctx, elastic.PerformRequestOptions{
+		Method: http.MethodGet,
+		Path:   "/" + strings.Join(names, ",") + "/_settings",
+		Params: map[string][]string{"filter_path": {"*.settings.index.creation_date"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("_settings request failed: %w", err)
+	}
+	var parsed settingsResponse
+	if err := json.Unmarshal(resp.Body, &parsed); err != nil {
+		return nil, fmt.Errorf("unmarshalling _settings response failed: %w", err)
+	}
+	resolved := make([]ResolvedIndex, 0, len(names))
+	for _, name := range names {
+		settings, ok := parsed[name]
+		if !ok {
+			continue
+		}
+		millis, err := strconv.ParseInt(settings.Settings.Index.CreationDate, 10, 64)
+		if err != nil {
+			continue
+		}
+		resolved = append(resolved, ResolvedIndex{
+			Name:         name,
+			CreationDate: time.UnixMilli(millis).UTC(),
+		})
+	}
+	return resolved, nil
+}
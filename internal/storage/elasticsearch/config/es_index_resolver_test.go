@@ -0,0 +1,85 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *elastic.Client {
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	client, err := elastic.NewClient(
+		elastic.SetURL(ts.URL),
+		elastic.SetSniff(false),
+		elastic.SetHealthcheck(false),
+	)
+	require.NoError(t, err)
+	return client
+}
+
+func TestESIndexResolver_ResolvesIndicesAndDataStreams(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/_resolve/index/jaeger-span-*":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"indices": []map[string]any{
+					{"name": "jaeger-span-2025-01-03"},
+				},
+				"data_streams": []map[string]any{
+					{"backing_indices": []string{".ds-jaeger-span-000001"}},
+				},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/jaeger-span-2025-01-03,.ds-jaeger-span-000001/_settings":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"jaeger-span-2025-01-03": map[string]any{
+					"settings": map[string]any{"index": map[string]any{"creation_date": "1735948800000"}},
+				},
+				".ds-jaeger-span-000001": map[string]any{
+					"settings": map[string]any{"index": map[string]any{"creation_date": "1735862400000"}},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	resolver := NewESIndexResolver(func() *elastic.Client { return client })
+
+	resolved, err := resolver.ResolveIndices(context.Background(), "jaeger-span-*")
+
+	require.NoError(t, err)
+	require.Len(t, resolved, 2)
+	names := []string{resolved[0].Name, resolved[1].Name}
+	require.ElementsMatch(t, []string{"jaeger-span-2025-01-03", ".ds-jaeger-span-000001"}, names)
+}
+
+func TestESIndexResolver_ReturnsErrResolveUnavailableOn404(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	resolver := NewESIndexResolver(func() *elastic.Client { return client })
+
+	_, err := resolver.ResolveIndices(context.Background(), "jaeger-span-*")
+
+	require.ErrorIs(t, err, ErrResolveUnavailable)
+}
+
+func TestESIndexResolver_NoMatchesReturnsEmpty(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"indices": []map[string]any{}})
+	})
+	resolver := NewESIndexResolver(func() *elastic.Client { return client })
+
+	resolved, err := resolver.ResolveIndices(context.Background(), "jaeger-span-*")
+
+	require.NoError(t, err)
+	require.Empty(t, resolved)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrResolveUnavailable is returned by an IndexResolver when the backing
+// Elasticsearch cluster predates the _resolve/index API (<7.9), so
+// ResolveIndices can fall back to the legacy date-walk.
+var ErrResolveUnavailable = errors.New("elasticsearch: _resolve/index API unavailable")
+
+// ResolvedIndex is a single concrete index or data-stream backing index
+// returned by the Elasticsearch _resolve/index API.
+type ResolvedIndex struct {
+	// Name is the concrete index (or backing index) name.
+	Name string
+	// CreationDate is the index's settings.index.creation_date; data-stream
+	// backing indices are dated the same way.
+	CreationDate time.Time
+}
+
+// IndexResolver resolves a wildcard pattern into the concrete indices and
+// data-stream backing indices behind it, via a single call to the
+// Elasticsearch GET _resolve/index/{pattern} API.
+type IndexResolver interface {
+	ResolveIndices(ctx context.Context, pattern string) ([]ResolvedIndex, error)
+}
+
+// ResolveIndices returns the concrete indices matching "prefix*" whose
+// creation date falls within [start, end], using a single call to the
+// Elasticsearch _resolve/index API through resolver. This replaces walking
+// every `indexRolloverFrequency` step between start and end and formatting
+// a candidate name for each one, which breaks whenever the rollover cadence
+// changes, ILM doesn't align to a fixed interval, or cold indices have been
+// deleted leaving gaps.
+//
+// If resolver is nil, or the call fails (e.g. ErrResolveUnavailable on a
+// pre-7.9 cluster), ResolveIndices falls back to dateWalk.
+func ResolveIndices(
+	ctx context.Context,
+	resolver IndexResolver,
+	prefix string,
+	start, end time.Time,
+	dateWalk func() []string,
+) []string {
+	if resolver == nil {
+		return dateWalk()
+	}
+	resolved, err := resolver.ResolveIndices(ctx, prefix+"*")
+	if err != nil {
+		return dateWalk()
+	}
+	var indices []string
+	for _, idx := range resolved {
+		if !idx.CreationDate.Before(start) && !idx.CreationDate.After(end) {
+			indices = append(indices, idx.Name)
+		}
+	}
+	if len(indices) == 0 {
+		return dateWalk()
+	}
+	sort.Strings(indices)
+	return indices
+}
+
+// ResolveLatestIndex returns the single most-recently-created concrete index
+// matching "prefix*", via one call to the Elasticsearch _resolve/index API
+// through resolver. This replaces walking backward one rollover period at a
+// time issuing a blocking IndexExists call per candidate.
+//
+// If resolver is nil, the call fails, or it resolves no indices, ResolveLatestIndex
+// falls back to dateWalk.
+func ResolveLatestIndex(
+	ctx context.Context,
+	resolver IndexResolver,
+	prefix string,
+	dateWalk func() ([]string, error),
+) ([]string, error) {
+	if resolver == nil {
+		return dateWalk()
+	}
+	resolved, err := resolver.ResolveIndices(ctx, prefix+"*")
+	if err != nil || len(resolved) == 0 {
+		return dateWalk()
+	}
+	latest := resolved[0]
+	for _, idx := range resolved[1:] {
+		if idx.CreationDate.After(latest.CreationDate) {
+			latest = idx
+		}
+	}
+	return []string{latest.Name}, nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResolver struct {
+	indices []ResolvedIndex
+	err     error
+}
+
+func (f fakeResolver) ResolveIndices(context.Context, string) ([]ResolvedIndex, error) {
+	return f.indices, f.err
+}
+
+func TestResolveIndices_FiltersByCreationDate(t *testing.T) {
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)
+	resolver := fakeResolver{indices: []ResolvedIndex{
+		{Name: "jaeger-span-2025-01-01", CreationDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "jaeger-span-2025-01-03", CreationDate: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{Name: "jaeger-span-2025-01-05", CreationDate: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+	}}
+
+	indices := ResolveIndices(context.Background(), resolver, "jaeger-span-", start, end, func() []string {
+		t.Fatal("dateWalk fallback should not be called")
+		return nil
+	})
+
+	assert.Equal(t, []string{"jaeger-span-2025-01-03"}, indices)
+}
+
+func TestResolveIndices_FallsBackWhenResolverUnavailable(t *testing.T) {
+	resolver := fakeResolver{err: ErrResolveUnavailable}
+	called := false
+
+	indices := ResolveIndices(context.Background(), resolver, "jaeger-span-", time.Now(), time.Now(), func() []string {
+		called = true
+		return []string{"jaeger-span-000001"}
+	})
+
+	assert.True(t, called)
+	assert.Equal(t, []string{"jaeger-span-000001"}, indices)
+}
+
+func TestResolveIndices_FallsBackWhenResolverNil(t *testing.T) {
+	called := false
+	indices := ResolveIndices(context.Background(), nil, "jaeger-span-", time.Now(), time.Now(), func() []string {
+		called = true
+		return []string{"jaeger-span-000001"}
+	})
+	assert.True(t, called)
+	assert.Equal(t, []string{"jaeger-span-000001"}, indices)
+}
+
+func TestGetDataStreamLegacyWildcard_Unbounded(t *testing.T) {
+	assert.Equal(t, []string{"jaeger-span-*"}, GetDataStreamLegacyWildcard("jaeger.span"))
+}
+
+func TestResolveLatestIndex_PicksMostRecentlyCreated(t *testing.T) {
+	resolver := fakeResolver{indices: []ResolvedIndex{
+		{Name: "jaeger-span-2025-01-01", CreationDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "jaeger-span-2025-01-05", CreationDate: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{Name: "jaeger-span-2025-01-03", CreationDate: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+	}}
+
+	indices, err := ResolveLatestIndex(context.Background(), resolver, "jaeger-span-", func() ([]string, error) {
+		t.Fatal("dateWalk fallback should not be called")
+		return nil, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"jaeger-span-2025-01-05"}, indices)
+}
+
+func TestResolveLatestIndex_FallsBackWhenResolverUnavailable(t *testing.T) {
+	resolver := fakeResolver{err: ErrResolveUnavailable}
+	called := false
+
+	indices, err := ResolveLatestIndex(context.Background(), resolver, "jaeger-span-", func() ([]string, error) {
+		called = true
+		return []string{"jaeger-span-000001"}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, []string{"jaeger-span-000001"}, indices)
+}
+
+func TestResolveLatestIndex_FallsBackWhenNoMatches(t *testing.T) {
+	resolver := fakeResolver{}
+	called := false
+
+	indices, err := ResolveLatestIndex(context.Background(), resolver, "jaeger-span-", func() ([]string, error) {
+		called = true
+		return []string{"jaeger-span-000001"}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, []string{"jaeger-span-000001"}, indices)
+}
+
+func TestResolveLatestIndex_FallsBackWhenResolverNil(t *testing.T) {
+	called := false
+	indices, err := ResolveLatestIndex(context.Background(), nil, "jaeger-span-", func() ([]string, error) {
+		called = true
+		return []string{"jaeger-span-000001"}, nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, []string{"jaeger-span-000001"}, indices)
+}
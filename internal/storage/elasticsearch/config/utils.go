@@ -13,9 +13,14 @@ func IndexWithDate(indexPrefix, indexDateLayout string, date time.Time) string {
 	return indexPrefix + date.UTC().Format(indexDateLayout)
 }
 
-// GetDataStreamLegacyWildcard returns the legacy wildcard pattern for a data stream.
-// It replaces the first dot with a dash and appends a wildcard.
-// Example: jaeger.span -> jaeger-span-*
-func GetDataStreamLegacyWildcard(dataStreamName string) string {
-	return strings.Replace(dataStreamName, ".", "-", 1) + "-*"
+// GetDataStreamLegacyWildcard returns the wildcard pattern matching every
+// backing index of a data stream. It replaces the first dot with a dash and
+// appends a wildcard, e.g. jaeger.span -> jaeger-span-*. It is a fallback for
+// callers that can't use IndexResolver (e.g. an Elasticsearch cluster older
+// than 7.9, with no _resolve/index API to bound the read to a date range);
+// callers that do have a resolver should filter via ResolveIndices instead of
+// fanning out to every backing index the stream has ever rolled over.
+func GetDataStreamLegacyWildcard(dataStreamName string) []string {
+	dashName := strings.Replace(dataStreamName, ".", "-", 1)
+	return []string{dashName + "-*"}
 }
@@ -0,0 +1,67 @@
+// Copyright (c) 2017 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jaegertracing/jaeger-idl/model/v1"
+)
+
+// ErrTraceNotFound is returned by Reader.GetTrace when no trace matching the
+// requested ID exists in the backend.
+var ErrTraceNotFound = errors.New("trace not found")
+
+// GetTraceParameters contains parameters for GetTrace.
+type GetTraceParameters struct {
+	TraceID   model.TraceID
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// OperationQueryParameters contains parameters for GetOperations.
+type OperationQueryParameters struct {
+	ServiceName string
+	SpanKind    string
+}
+
+// Operation contains operation and span kind.
+type Operation struct {
+	Name     string
+	SpanKind string
+}
+
+// TraceQueryParameters contains parameters for FindTraces and FindTraceIDs.
+type TraceQueryParameters struct {
+	ServiceName   string
+	OperationName string
+	Tags          map[string]string
+	StartTimeMin  time.Time
+	StartTimeMax  time.Time
+	DurationMin   time.Duration
+	DurationMax   time.Duration
+	NumTraces     int
+}
+
+// Reader finds and loads traces and other data from storage.
+type Reader interface {
+	// GetTrace retrieves the trace with a given id.
+	//
+	// If no spans are stored for this trace, it returns ErrTraceNotFound.
+	GetTrace(ctx context.Context, query GetTraceParameters) (*model.Trace, error)
+
+	// GetServices returns all service names known to the backend.
+	GetServices(ctx context.Context) ([]string, error)
+
+	// GetOperations returns all operations for a specific service known to the backend.
+	GetOperations(ctx context.Context, query OperationQueryParameters) ([]Operation, error)
+
+	// FindTraces retrieves traces that match the traceQuery.
+	FindTraces(ctx context.Context, query *TraceQueryParameters) ([]*model.Trace, error)
+
+	// FindTraceIDs retrieves only the TraceIDs that match the traceQuery, without loading the spans.
+	FindTraceIDs(ctx context.Context, query *TraceQueryParameters) ([]model.TraceID, error)
+}
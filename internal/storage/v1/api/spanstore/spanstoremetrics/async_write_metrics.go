@@ -0,0 +1,276 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstoremetrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/jaegertracing/jaeger/internal/metrics"
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore"
+)
+
+// ErrAsyncWriterClosed is returned by AsyncWriterDecorator.WriteSpan once the
+// decorator has started shutting down.
+var ErrAsyncWriterClosed = errors.New("async writer is shutting down")
+
+// OverflowPolicy controls what AsyncWriterDecorator does when its queue is full.
+type OverflowPolicy string
+
+const (
+	// OverflowBlock blocks WriteSpan until there is room on the queue.
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDropNewest discards the span being enqueued, keeping the queue as-is.
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+	// OverflowDropOldest discards the oldest queued span to make room for the new one.
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+)
+
+// AsyncWriterOptions configures AsyncWriterDecorator.
+type AsyncWriterOptions struct {
+	// QueueSize is the number of spans buffered between WriteSpan and the worker pool.
+	QueueSize int
+	// Workers is the number of goroutines draining the queue concurrently.
+	Workers int
+	// OverflowPolicy decides what happens when the queue is full. Defaults to OverflowBlock.
+	OverflowPolicy OverflowPolicy
+	// MaxRetries bounds how many times a failed write is retried before being
+	// dropped. Zero/unset defaults to 3, matching every other *<=0* default in
+	// this struct.
+	MaxRetries int
+	// RetryBaseDelay and RetryMaxDelay bound the exponential backoff between write retries.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// ShutdownTimeout bounds how long Close waits for the queue to drain.
+	ShutdownTimeout time.Duration
+	// OnDropped, if set, is called when a span is dropped after its retries are
+	// exhausted on a persistent write error. Spans dropped because the queue is
+	// full or because shutdown is in progress are counted in the dropped
+	// metrics but are not passed to OnDropped.
+	OnDropped func(ctx context.Context, span *model.Span, err error)
+}
+
+func (o *AsyncWriterOptions) applyDefaults() {
+	if o.QueueSize <= 0 {
+		o.QueueSize = 1000
+	}
+	if o.Workers <= 0 {
+		o.Workers = 1
+	}
+	if o.OverflowPolicy == "" {
+		o.OverflowPolicy = OverflowBlock
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = 3
+	}
+	if o.RetryBaseDelay <= 0 {
+		o.RetryBaseDelay = 100 * time.Millisecond
+	}
+	if o.RetryMaxDelay <= 0 {
+		o.RetryMaxDelay = 30 * time.Second
+	}
+	if o.ShutdownTimeout <= 0 {
+		o.ShutdownTimeout = 30 * time.Second
+	}
+}
+
+// asyncWriterMetrics holds the queue saturation signals recorded by AsyncWriterDecorator.
+type asyncWriterMetrics struct {
+	Enqueued        metrics.Counter `metric:"enqueued"`
+	Dequeued        metrics.Counter `metric:"dequeued"`
+	DroppedFull     metrics.Counter `metric:"dropped" tags:"reason=full"`
+	DroppedShutdown metrics.Counter `metric:"dropped" tags:"reason=shutdown"`
+	DroppedFailed   metrics.Counter `metric:"dropped" tags:"reason=failed"`
+	QueueDepth      metrics.Gauge   `metric:"queue_depth"`
+	EnqueueLatency  metrics.Timer   `metric:"enqueue_to_write_latency"`
+}
+
+type queuedSpan struct {
+	ctx        context.Context
+	span       *model.Span
+	enqueuedAt time.Time
+}
+
+// AsyncWriterDecorator fronts a spanstore.Writer with a bounded queue and a
+// pool of worker goroutines, so that a burst of WriteSpan calls is smoothed
+// into the backend instead of stalling the caller's hot path.
+type AsyncWriterDecorator struct {
+	writer spanstore.Writer
+	opts   AsyncWriterOptions
+	mf     metrics.Factory
+	stats  *asyncWriterMetrics
+
+	queue  chan queuedSpan
+	closed atomic.Bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewAsyncWriterDecorator returns an AsyncWriterDecorator fronting writer.
+func NewAsyncWriterDecorator(writer spanstore.Writer, opts AsyncWriterOptions, mf metrics.Factory) *AsyncWriterDecorator {
+	opts.applyDefaults()
+	stats := new(asyncWriterMetrics)
+	metrics.Init(stats, mf, nil)
+	a := &AsyncWriterDecorator{
+		writer: writer,
+		opts:   opts,
+		mf:     mf,
+		stats:  stats,
+		queue:  make(chan queuedSpan, opts.QueueSize),
+		done:   make(chan struct{}),
+	}
+	a.wg.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go a.worker()
+	}
+	return a
+}
+
+// WriteSpan enqueues span for asynchronous writing, applying OverflowPolicy if the queue is full.
+func (a *AsyncWriterDecorator) WriteSpan(ctx context.Context, span *model.Span) error {
+	if a.closed.Load() {
+		a.stats.DroppedShutdown.Inc(1)
+		return ErrAsyncWriterClosed
+	}
+	item := queuedSpan{ctx: ctx, span: span, enqueuedAt: time.Now()}
+	var err error
+	switch a.opts.OverflowPolicy {
+	case OverflowDropNewest:
+		a.enqueueDroppingNewest(item)
+	case OverflowDropOldest:
+		a.enqueueDroppingOldest(item)
+	default: // OverflowBlock
+		err = a.enqueueBlocking(item)
+	}
+	a.stats.QueueDepth.Update(int64(len(a.queue)))
+	return err
+}
+
+// enqueueBlocking blocks until there is room on the queue, but races that
+// wait against Close so a producer can never be left parked on a channel
+// nobody will read from again once the worker pool has shut down.
+func (a *AsyncWriterDecorator) enqueueBlocking(item queuedSpan) error {
+	select {
+	case a.queue <- item:
+		a.stats.Enqueued.Inc(1)
+		return nil
+	case <-a.done:
+		a.stats.DroppedShutdown.Inc(1)
+		return ErrAsyncWriterClosed
+	}
+}
+
+func (a *AsyncWriterDecorator) enqueueDroppingNewest(item queuedSpan) {
+	select {
+	case a.queue <- item:
+		a.stats.Enqueued.Inc(1)
+	default:
+		a.stats.DroppedFull.Inc(1)
+	}
+}
+
+func (a *AsyncWriterDecorator) enqueueDroppingOldest(item queuedSpan) {
+	for {
+		select {
+		case a.queue <- item:
+			a.stats.Enqueued.Inc(1)
+			return
+		default:
+			select {
+			case <-a.queue:
+				a.stats.DroppedFull.Inc(1)
+			default:
+			}
+		}
+	}
+}
+
+func (a *AsyncWriterDecorator) worker() {
+	defer a.wg.Done()
+	for {
+		select {
+		case item := <-a.queue:
+			a.process(item)
+		case <-a.done:
+			a.drain()
+			return
+		}
+	}
+}
+
+// drain flushes whatever remains queued, without blocking for more to arrive.
+func (a *AsyncWriterDecorator) drain() {
+	for {
+		select {
+		case item := <-a.queue:
+			a.process(item)
+		default:
+			return
+		}
+	}
+}
+
+func (a *AsyncWriterDecorator) process(item queuedSpan) {
+	a.stats.Dequeued.Inc(1)
+	a.stats.EnqueueLatency.Record(time.Since(item.enqueuedAt))
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = a.writer.WriteSpan(item.ctx, item.span)
+		if err == nil {
+			return
+		}
+		if attempt >= a.opts.MaxRetries {
+			a.stats.DroppedFailed.Inc(1)
+			if a.opts.OnDropped != nil {
+				a.opts.OnDropped(item.ctx, item.span, err)
+			}
+			return
+		}
+		a.retriedCounter(attempt + 1).Inc(1)
+		time.Sleep(backoffDelay(attempt, a.opts.RetryBaseDelay, a.opts.RetryMaxDelay))
+	}
+}
+
+func (a *AsyncWriterDecorator) retriedCounter(attempt int) metrics.Counter {
+	return a.mf.Counter(metrics.Options{
+		Name: "retried",
+		Tags: map[string]string{"attempt": fmt.Sprintf("%d", attempt)},
+	})
+}
+
+func backoffDelay(attempt int, base, cap_ time.Duration) time.Duration {
+	delay := float64(base) * math.Pow(2, float64(attempt))
+	if delay <= 0 || delay > float64(cap_) {
+		delay = float64(cap_)
+	}
+	return time.Duration(delay)
+}
+
+// Close stops accepting new spans and waits, up to ShutdownTimeout, for the
+// queue to drain through the worker pool.
+func (a *AsyncWriterDecorator) Close() error {
+	a.closed.Store(true)
+	close(a.done)
+
+	drained := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-time.After(a.opts.ShutdownTimeout):
+		return fmt.Errorf("async writer: queue did not drain within %s", a.opts.ShutdownTimeout)
+	}
+}
+
+var _ spanstore.Writer = (*AsyncWriterDecorator)(nil)
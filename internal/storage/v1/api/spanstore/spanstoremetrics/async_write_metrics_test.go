@@ -0,0 +1,158 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstoremetrics_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore/spanstoremetrics"
+)
+
+type countingWriter struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (w *countingWriter) WriteSpan(context.Context, *model.Span) error {
+	w.calls.Add(1)
+	return w.err
+}
+
+func TestAsyncWriterDecorator_DrainsOnClose(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	writer := &countingWriter{}
+	async := spanstoremetrics.NewAsyncWriterDecorator(writer, spanstoremetrics.AsyncWriterOptions{
+		QueueSize: 10,
+		Workers:   2,
+	}, mf)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, async.WriteSpan(context.Background(), &model.Span{}))
+	}
+	require.NoError(t, async.Close())
+
+	assert.EqualValues(t, 5, writer.calls.Load())
+
+	counters, _ := mf.Snapshot()
+	assert.Equal(t, int64(5), counters["enqueued"])
+	assert.Equal(t, int64(5), counters["dequeued"])
+}
+
+func TestAsyncWriterDecorator_DropNewestWhenFull(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	block := make(chan struct{})
+	started := make(chan struct{}, 1)
+	writer := &blockingWriter{block: block, started: started}
+	async := spanstoremetrics.NewAsyncWriterDecorator(writer, spanstoremetrics.AsyncWriterOptions{
+		QueueSize:      1,
+		Workers:        1,
+		OverflowPolicy: spanstoremetrics.OverflowDropNewest,
+	}, mf)
+
+	// First write is picked up by the single worker and blocks there, second
+	// fills the queue (capacity 1), the rest should be dropped as full.
+	require.NoError(t, async.WriteSpan(context.Background(), &model.Span{}))
+	<-started
+	require.NoError(t, async.WriteSpan(context.Background(), &model.Span{}))
+	require.NoError(t, async.WriteSpan(context.Background(), &model.Span{}))
+
+	close(block)
+	require.NoError(t, async.Close())
+
+	counters, _ := mf.Snapshot()
+	assert.GreaterOrEqual(t, counters["dropped|reason=full"], int64(1))
+}
+
+func TestAsyncWriterDecorator_RejectsAfterClose(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	async := spanstoremetrics.NewAsyncWriterDecorator(&countingWriter{}, spanstoremetrics.AsyncWriterOptions{QueueSize: 1}, mf)
+	require.NoError(t, async.Close())
+
+	err := async.WriteSpan(context.Background(), &model.Span{})
+	assert.ErrorIs(t, err, spanstoremetrics.ErrAsyncWriterClosed)
+}
+
+func TestAsyncWriterDecorator_BlockedWriteUnblocksOnClose(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	block := make(chan struct{})
+	started := make(chan struct{}, 1)
+	writer := &blockingWriter{block: block, started: started}
+	async := spanstoremetrics.NewAsyncWriterDecorator(writer, spanstoremetrics.AsyncWriterOptions{
+		QueueSize:      1,
+		Workers:        1,
+		OverflowPolicy: spanstoremetrics.OverflowBlock,
+	}, mf)
+
+	// Worker picks up the first span and blocks on it; the second fills the
+	// single-slot queue; a third has nowhere to go and must block in
+	// WriteSpan until Close unblocks it instead of hanging forever.
+	require.NoError(t, async.WriteSpan(context.Background(), &model.Span{}))
+	<-started
+	require.NoError(t, async.WriteSpan(context.Background(), &model.Span{}))
+
+	thirdDone := make(chan error, 1)
+	go func() {
+		thirdDone <- async.WriteSpan(context.Background(), &model.Span{})
+	}()
+
+	go func() {
+		close(block)
+		_ = async.Close()
+	}()
+
+	select {
+	case err := <-thirdDone:
+		assert.ErrorIs(t, err, spanstoremetrics.ErrAsyncWriterClosed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("WriteSpan blocked forever past Close")
+	}
+}
+
+func TestAsyncWriterDecorator_DropsAfterRetriesExhausted(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	writer := &countingWriter{err: errors.New("boom")}
+	var dropped []error
+	async := spanstoremetrics.NewAsyncWriterDecorator(writer, spanstoremetrics.AsyncWriterOptions{
+		QueueSize:      1,
+		Workers:        1,
+		MaxRetries:     1,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  time.Millisecond,
+		OnDropped: func(_ context.Context, _ *model.Span, err error) {
+			dropped = append(dropped, err)
+		},
+	}, mf)
+
+	require.NoError(t, async.WriteSpan(context.Background(), &model.Span{}))
+	require.NoError(t, async.Close())
+
+	counters, _ := mf.Snapshot()
+	assert.Equal(t, int64(1), counters["dropped|reason=failed"])
+	require.Len(t, dropped, 1)
+	assert.EqualError(t, dropped[0], "boom")
+}
+
+type blockingWriter struct {
+	block   chan struct{}
+	started chan struct{}
+	err     error
+}
+
+func (w *blockingWriter) WriteSpan(context.Context, *model.Span) error {
+	select {
+	case w.started <- struct{}{}:
+	default:
+	}
+	<-w.block
+	return w.err
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstoremetrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore"
+)
+
+// Result labels used for the "requests" and "latency" metrics, replacing the
+// old binary ok/err label with a fixed, actionable vocabulary.
+const (
+	ResultOK              = "ok"
+	ResultNotFound        = "not_found"
+	ResultTimeout         = "timeout"
+	ResultCanceled        = "canceled"
+	ResultUnavailable     = "unavailable"
+	ResultInvalidArgument = "invalid_argument"
+	ResultInternal        = "internal"
+)
+
+// ErrorClassifier maps an error to one of the Result* classes above. An empty
+// return value means "no opinion" and lets the next classifier in the chain decide.
+type ErrorClassifier func(err error) string
+
+var (
+	registryMu sync.RWMutex
+	registry   []ErrorClassifier
+)
+
+// RegisterErrorClassifier lets a storage backend package (Cassandra,
+// Elasticsearch, ...) contribute error->class mappings specific to its
+// driver (e.g. gocql ErrNotFound -> not_found, Elasticsearch 429 ->
+// unavailable). Registered classifiers are consulted, in registration
+// order, before DefaultErrorClassifier.
+func RegisterErrorClassifier(c ErrorClassifier) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, c)
+}
+
+// ResetRegisteredClassifiersForTest clears every classifier registered via
+// RegisterErrorClassifier. It exists only so tests that register a
+// classifier can restore the shared registry afterwards via t.Cleanup,
+// instead of leaking state into every other test in the binary; no
+// production caller should ever need to undo a registration.
+func ResetRegisteredClassifiersForTest() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = nil
+}
+
+// Classify resolves err to a Result* class: override first, then any
+// registered backend classifiers, then DefaultErrorClassifier.
+func Classify(err error, override ErrorClassifier) string {
+	if err == nil {
+		return ResultOK
+	}
+	if override != nil {
+		if class := override(err); class != "" {
+			return class
+		}
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, c := range registry {
+		if class := c(err); class != "" {
+			return class
+		}
+	}
+	return DefaultErrorClassifier(err)
+}
+
+// DefaultErrorClassifier classifies the errors common to every spanstore
+// implementation (context cancellation/deadlines and ErrTraceNotFound),
+// falling back to ResultInternal for anything backend-specific that no
+// registered classifier recognized.
+func DefaultErrorClassifier(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ResultTimeout
+	case errors.Is(err, context.Canceled):
+		return ResultCanceled
+	case errors.Is(err, spanstore.ErrTraceNotFound):
+		return ResultNotFound
+	default:
+		return ResultInternal
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstoremetrics_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore"
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore/spanstoremetrics"
+)
+
+func TestClassify_DefaultClassifier(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, spanstoremetrics.ResultOK},
+		{"deadline exceeded", context.DeadlineExceeded, spanstoremetrics.ResultTimeout},
+		{"canceled", context.Canceled, spanstoremetrics.ResultCanceled},
+		{"trace not found", spanstore.ErrTraceNotFound, spanstoremetrics.ResultNotFound},
+		{"unmapped", errors.New("boom"), spanstoremetrics.ResultInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, spanstoremetrics.Classify(tt.err, nil))
+		})
+	}
+}
+
+func TestClassify_OverrideTakesPrecedence(t *testing.T) {
+	override := func(error) string { return spanstoremetrics.ResultUnavailable }
+	assert.Equal(t, spanstoremetrics.ResultUnavailable, spanstoremetrics.Classify(errors.New("boom"), override))
+}
+
+func TestClassify_RegisteredClassifierConsultedBeforeDefault(t *testing.T) {
+	t.Cleanup(spanstoremetrics.ResetRegisteredClassifiersForTest)
+	sentinel := errors.New("429 Too Many Requests")
+	spanstoremetrics.RegisterErrorClassifier(func(err error) string {
+		if errors.Is(err, sentinel) {
+			return spanstoremetrics.ResultUnavailable
+		}
+		return ""
+	})
+
+	assert.Equal(t, spanstoremetrics.ResultUnavailable, spanstoremetrics.Classify(sentinel, nil))
+}
@@ -0,0 +1,370 @@
+// Copyright (c) 2017 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstoremetrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/jaegertracing/jaeger/internal/metrics"
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore"
+	"github.com/jaegertracing/jaeger/internal/tenancy"
+)
+
+// overflowTenant is the tag value used once a request's tenant isn't in the
+// allowlist, or the number of distinct tenants seen exceeds MaxCardinality.
+const overflowTenant = "_other_"
+
+// Options configures a ReaderDecorator.
+type Options struct {
+	// ErrorClassifier maps a non-nil error to one of the Result* classes
+	// before DefaultErrorClassifier and the backend registry are consulted.
+	// A nil or empty-string result means "no opinion, try the next classifier".
+	ErrorClassifier ErrorClassifier
+	// TenantLabel, if enabled, adds a tenant=<id> tag to every counter and
+	// histogram, with the tenant extracted from context via the tenancy package.
+	TenantLabel TenantLabelOptions
+	// SlowQueryThreshold, if positive, turns on slow-query reporting: any call
+	// taking at least this long increments a slow_queries|operation=... counter
+	// and, when Logger/Tracer are set, logs a redacted record of the query and
+	// annotates the surrounding span if it is already being recorded (see
+	// Tracer). Zero (the default) disables this.
+	SlowQueryThreshold time.Duration
+	// Logger receives one warning per slow query. Required for slow-query
+	// logging; the counter is incremented regardless of whether it is set.
+	Logger *zap.Logger
+	// Tracer, if set, has the span active in the call's context annotated
+	// with the slow query's attributes. This only enriches a span that is
+	// already being recorded (e.g. sampled in by the head sampler); OTel
+	// spans make their sampling decision at Start and it cannot be reversed
+	// after the fact, so a slow call on a span that was sampled out stays
+	// invisible to tracing. The slow_queries counter and Logger are the only
+	// signals guaranteed to fire regardless of the sampling decision.
+	Tracer trace.Tracer
+}
+
+// TenantLabelOptions bounds the cardinality a per-tenant label can add to metrics.
+type TenantLabelOptions struct {
+	// Enabled turns on the tenant=<id> tag. Disabled by default: a context
+	// without tenancy (or with tenancy disabled here) produces the same
+	// single-dimension metrics as before.
+	Enabled bool
+	// Allowlist, if non-nil, is the fixed set of tenants allowed their own
+	// label value; anything else collapses into overflowTenant.
+	Allowlist map[string]bool
+	// MaxCardinality caps the number of distinct tenant label values emitted
+	// when Allowlist is nil; tenants beyond the cap collapse into
+	// overflowTenant. Zero means unbounded (not recommended in production).
+	MaxCardinality int
+}
+
+// classMetrics holds the counters/timer recorded for one operation+result(+tenant) class.
+type classMetrics struct {
+	Requests metrics.Counter
+	Latency  metrics.Timer
+}
+
+// queryMetrics holds the per-class (and optionally per-tenant) metrics for a
+// single Reader operation, created lazily since the set of result classes
+// and tenants is not known up front.
+type queryMetrics struct {
+	operation  string
+	mf         metrics.Factory
+	tenantOpts TenantLabelOptions
+
+	mu          sync.Mutex
+	byKey       map[string]*classMetrics
+	responses   map[string]metrics.Timer // keyed by tenant ("" when TenantLabel is disabled)
+	seenTenants map[string]bool
+}
+
+func buildQueryMetrics(operation string, mf metrics.Factory, tenantOpts TenantLabelOptions) *queryMetrics {
+	return &queryMetrics{
+		operation:  operation,
+		mf:         mf,
+		tenantOpts: tenantOpts,
+		byKey:      make(map[string]*classMetrics),
+		responses:  make(map[string]metrics.Timer),
+	}
+}
+
+// resolveTenant applies the allowlist/cardinality cap, returning "" when
+// TenantLabel is disabled or the context carries no tenant.
+func (q *queryMetrics) resolveTenant(ctx context.Context) string {
+	if !q.tenantOpts.Enabled {
+		return ""
+	}
+	tenant := tenancy.GetTenant(ctx)
+	if tenant == "" {
+		return ""
+	}
+	if q.tenantOpts.Allowlist != nil {
+		if q.tenantOpts.Allowlist[tenant] {
+			return tenant
+		}
+		return overflowTenant
+	}
+	if q.tenantOpts.MaxCardinality <= 0 {
+		return tenant
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.seenTenants == nil {
+		q.seenTenants = make(map[string]bool)
+	}
+	if q.seenTenants[tenant] {
+		return tenant
+	}
+	if len(q.seenTenants) >= q.tenantOpts.MaxCardinality {
+		return overflowTenant
+	}
+	q.seenTenants[tenant] = true
+	return tenant
+}
+
+func (q *queryMetrics) classMetricsFor(class, tenant string) *classMetrics {
+	key := tenant + "|" + class
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if cm, ok := q.byKey[key]; ok {
+		return cm
+	}
+	tags := map[string]string{"operation": q.operation, "result": class}
+	if tenant != "" {
+		tags["tenant"] = tenant
+	}
+	cm := &classMetrics{
+		Requests: q.mf.Counter(metrics.Options{Name: "requests", Tags: tags}),
+		Latency:  q.mf.Timer(metrics.TimerOptions{Name: "latency", Tags: tags}),
+	}
+	q.byKey[key] = cm
+	return cm
+}
+
+func (q *queryMetrics) responsesFor(tenant string) metrics.Timer {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if t, ok := q.responses[tenant]; ok {
+		return t
+	}
+	tags := map[string]string{"operation": q.operation}
+	if tenant != "" {
+		tags["tenant"] = tenant
+	}
+	t := q.mf.Timer(metrics.TimerOptions{Name: "responses", Tags: tags})
+	q.responses[tenant] = t
+	return t
+}
+
+// slowQueryInfo carries the parameters of a slow call for logging/tracing.
+// Tag values are deliberately never included, only their keys and count: the
+// tags passed to FindTraces can carry user data, and only keys and
+// cardinality are safe to surface by default.
+type slowQueryInfo struct {
+	Service   string
+	Operation string
+	TagKeys   []string
+	Lookback  time.Duration
+	Limit     int
+}
+
+// ReaderDecorator wraps a spanstore.Reader and records request counts, latency
+// and response sizes for each operation into a metrics.Factory.
+type ReaderDecorator struct {
+	spanReader spanstore.Reader
+	classifier ErrorClassifier
+	mf         metrics.Factory
+
+	slowQueryThreshold time.Duration
+	logger             *zap.Logger
+	tracer             trace.Tracer
+
+	findTracesMetrics    *queryMetrics
+	findTraceIDsMetrics  *queryMetrics
+	getTraceMetrics      *queryMetrics
+	getServicesMetrics   *queryMetrics
+	getOperationsMetrics *queryMetrics
+}
+
+// NewReaderDecorator returns a new ReaderDecorator using the default error
+// classification and no tenant dimension. Use NewReaderDecoratorWithOptions to customize it.
+func NewReaderDecorator(reader spanstore.Reader, mf metrics.Factory) *ReaderDecorator {
+	return NewReaderDecoratorWithOptions(reader, mf, Options{})
+}
+
+// NewReaderDecoratorWithOptions returns a new ReaderDecorator configured by opts.
+func NewReaderDecoratorWithOptions(reader spanstore.Reader, mf metrics.Factory, opts Options) *ReaderDecorator {
+	return &ReaderDecorator{
+		spanReader:           reader,
+		classifier:           opts.ErrorClassifier,
+		mf:                   mf,
+		slowQueryThreshold:   opts.SlowQueryThreshold,
+		logger:               opts.Logger,
+		tracer:               opts.Tracer,
+		findTracesMetrics:    buildQueryMetrics("find_traces", mf, opts.TenantLabel),
+		findTraceIDsMetrics:  buildQueryMetrics("find_trace_ids", mf, opts.TenantLabel),
+		getTraceMetrics:      buildQueryMetrics("get_trace", mf, opts.TenantLabel),
+		getServicesMetrics:   buildQueryMetrics("get_services", mf, opts.TenantLabel),
+		getOperationsMetrics: buildQueryMetrics("get_operations", mf, opts.TenantLabel),
+	}
+}
+
+func (m *ReaderDecorator) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	start := time.Now()
+	trace, err := m.spanReader.GetTrace(ctx, query)
+	latency := time.Since(start)
+	m.emit(ctx, m.getTraceMetrics, latency, err, 1)
+	m.reportSlowQuery(ctx, "get_trace", slowQueryInfo{}, latency, 1, err)
+	return trace, err
+}
+
+func (m *ReaderDecorator) GetServices(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	services, err := m.spanReader.GetServices(ctx)
+	latency := time.Since(start)
+	m.emit(ctx, m.getServicesMetrics, latency, err, len(services))
+	m.reportSlowQuery(ctx, "get_services", slowQueryInfo{}, latency, len(services), err)
+	return services, err
+}
+
+func (m *ReaderDecorator) GetOperations(ctx context.Context, query spanstore.OperationQueryParameters) ([]spanstore.Operation, error) {
+	start := time.Now()
+	operations, err := m.spanReader.GetOperations(ctx, query)
+	latency := time.Since(start)
+	m.emit(ctx, m.getOperationsMetrics, latency, err, len(operations))
+	m.reportSlowQuery(ctx, "get_operations", slowQueryInfo{Service: query.ServiceName}, latency, len(operations), err)
+	return operations, err
+}
+
+func (m *ReaderDecorator) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	start := time.Now()
+	traces, err := m.spanReader.FindTraces(ctx, query)
+	latency := time.Since(start)
+	m.emit(ctx, m.findTracesMetrics, latency, err, len(traces))
+	m.reportSlowQuery(ctx, "find_traces", traceSlowQueryInfo(query), latency, len(traces), err)
+	return traces, err
+}
+
+func (m *ReaderDecorator) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	start := time.Now()
+	traceIDs, err := m.spanReader.FindTraceIDs(ctx, query)
+	latency := time.Since(start)
+	m.emit(ctx, m.findTraceIDsMetrics, latency, err, len(traceIDs))
+	m.reportSlowQuery(ctx, "find_trace_ids", traceSlowQueryInfo(query), latency, len(traceIDs), err)
+	return traceIDs, err
+}
+
+func traceSlowQueryInfo(query *spanstore.TraceQueryParameters) slowQueryInfo {
+	var keys []string
+	if len(query.Tags) > 0 {
+		keys = make([]string, 0, len(query.Tags))
+		for k := range query.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+	return slowQueryInfo{
+		Service:   query.ServiceName,
+		Operation: query.OperationName,
+		TagKeys:   keys,
+		Lookback:  query.StartTimeMax.Sub(query.StartTimeMin),
+		Limit:     query.NumTraces,
+	}
+}
+
+func (m *ReaderDecorator) emit(ctx context.Context, qm *queryMetrics, latency time.Duration, err error, numResults int) {
+	class := Classify(err, m.classifier)
+	tenant := qm.resolveTenant(ctx)
+	cm := qm.classMetricsFor(class, tenant)
+	cm.Requests.Inc(1)
+	cm.Latency.Record(latency)
+	if err == nil {
+		qm.responsesFor(tenant).Record(time.Duration(numResults))
+	}
+}
+
+// reportSlowQuery increments the slow_queries counter and, when configured,
+// logs a redacted record of the call and annotates its active span if that
+// span is already being recorded. A zero SlowQueryThreshold (the default)
+// disables this entirely.
+func (m *ReaderDecorator) reportSlowQuery(ctx context.Context, operation string, info slowQueryInfo, latency time.Duration, numResults int, err error) {
+	if m.slowQueryThreshold <= 0 || latency < m.slowQueryThreshold {
+		return
+	}
+	m.mf.Counter(metrics.Options{Name: "slow_queries", Tags: map[string]string{"operation": operation}}).Inc(1)
+
+	if m.logger != nil {
+		fields := []zap.Field{
+			zap.String("operation", operation),
+			zap.Duration("duration", latency),
+			zap.Int("result_size", numResults),
+		}
+		if info.Service != "" {
+			fields = append(fields, zap.String("service", info.Service))
+		}
+		if info.Operation != "" {
+			fields = append(fields, zap.String("query_operation", info.Operation))
+		}
+		if len(info.TagKeys) > 0 {
+			fields = append(fields, zap.Strings("tag_keys", info.TagKeys), zap.Int("num_tags", len(info.TagKeys)))
+		}
+		if info.Lookback > 0 {
+			fields = append(fields, zap.Duration("lookback", info.Lookback))
+		}
+		if info.Limit > 0 {
+			fields = append(fields, zap.Int("limit", info.Limit))
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+		m.logger.Warn("slow query", fields...)
+	}
+
+	if m.tracer == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	// SetAttributes on a non-recording span (e.g. one sampled out by the head
+	// sampler) is a documented no-op: there is no way to reverse an OTel
+	// sampling decision after Start, so a query that was never sampled in
+	// stays invisible to tracing regardless of how slow it turns out to be.
+	// The slow_queries counter and Logger above are what operators should
+	// rely on for that case; this only enriches spans already being recorded.
+	if !span.IsRecording() {
+		return
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("operation", operation),
+		attribute.Int64("duration_ms", latency.Milliseconds()),
+		attribute.Int("result_size", numResults),
+	}
+	if info.Service != "" {
+		attrs = append(attrs, attribute.String("service", info.Service))
+	}
+	if info.Operation != "" {
+		attrs = append(attrs, attribute.String("query_operation", info.Operation))
+	}
+	if len(info.TagKeys) > 0 {
+		attrs = append(attrs, attribute.StringSlice("tag_keys", info.TagKeys), attribute.Int("num_tags", len(info.TagKeys)))
+	}
+	if info.Lookback > 0 {
+		attrs = append(attrs, attribute.String("lookback", info.Lookback.String()))
+	}
+	if info.Limit > 0 {
+		attrs = append(attrs, attribute.Int("limit", info.Limit))
+	}
+	span.SetAttributes(attrs...)
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+var _ spanstore.Reader = (*ReaderDecorator)(nil)
@@ -85,16 +85,16 @@ func TestSuccessfulUnderlyingCalls(t *testing.T) {
 	mrs.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{})
 	counters, gauges := mf.Snapshot()
 	expecteds := map[string]int64{
-		"requests|operation=get_operations|result=ok":  1,
-		"requests|operation=get_operations|result=err": 0,
-		"requests|operation=get_trace|result=ok":       1,
-		"requests|operation=get_trace|result=err":      0,
-		"requests|operation=find_traces|result=ok":     1,
-		"requests|operation=find_traces|result=err":    0,
-		"requests|operation=find_trace_ids|result=ok":  1,
-		"requests|operation=find_trace_ids|result=err": 0,
-		"requests|operation=get_services|result=ok":    1,
-		"requests|operation=get_services|result=err":   0,
+		"requests|operation=get_operations|result=ok":       1,
+		"requests|operation=get_operations|result=internal": 0,
+		"requests|operation=get_trace|result=ok":             1,
+		"requests|operation=get_trace|result=internal":       0,
+		"requests|operation=find_traces|result=ok":           1,
+		"requests|operation=find_traces|result=internal":     0,
+		"requests|operation=find_trace_ids|result=ok":        1,
+		"requests|operation=find_trace_ids|result=internal":  0,
+		"requests|operation=get_services|result=ok":          1,
+		"requests|operation=get_services|result=internal":    0,
 	}
 
 	existingKeys := []string{
@@ -103,7 +103,7 @@ func TestSuccessfulUnderlyingCalls(t *testing.T) {
 		"latency|operation=find_traces|result=ok.P50", // this is not exhaustive
 	}
 	nonExistentKeys := []string{
-		"latency|operation=get_operations|result=err.P50",
+		"latency|operation=get_operations|result=internal.P50",
 	}
 
 	checkExpectedExistingAndNonExistentCounters(t, counters, expecteds, gauges, existingKeys, nonExistentKeys)
@@ -154,20 +154,20 @@ func TestFailingUnderlyingCalls(t *testing.T) {
 	mrs.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{})
 	counters, gauges := mf.Snapshot()
 	expecteds := map[string]int64{
-		"requests|operation=get_operations|result=ok":  0,
-		"requests|operation=get_operations|result=err": 1,
-		"requests|operation=get_trace|result=ok":       0,
-		"requests|operation=get_trace|result=err":      1,
-		"requests|operation=find_traces|result=ok":     0,
-		"requests|operation=find_traces|result=err":    1,
-		"requests|operation=find_trace_ids|result=ok":  0,
-		"requests|operation=find_trace_ids|result=err": 1,
-		"requests|operation=get_services|result=ok":    0,
-		"requests|operation=get_services|result=err":   1,
+		"requests|operation=get_operations|result=ok":       0,
+		"requests|operation=get_operations|result=internal": 1,
+		"requests|operation=get_trace|result=ok":             0,
+		"requests|operation=get_trace|result=internal":       1,
+		"requests|operation=find_traces|result=ok":           0,
+		"requests|operation=find_traces|result=internal":     1,
+		"requests|operation=find_trace_ids|result=ok":        0,
+		"requests|operation=find_trace_ids|result=internal":  1,
+		"requests|operation=get_services|result=ok":          0,
+		"requests|operation=get_services|result=internal":    1,
 	}
 
 	existingKeys := []string{
-		"latency|operation=get_operations|result=err.P50",
+		"latency|operation=get_operations|result=internal.P50",
 	}
 
 	nonExistentKeys := []string{
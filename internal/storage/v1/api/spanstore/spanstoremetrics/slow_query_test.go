@@ -0,0 +1,126 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstoremetrics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore"
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore/spanstoremetrics"
+)
+
+func TestReaderDecorator_SlowQueryDisabledByDefault(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	core, observed := observer.New(zap.WarnLevel)
+	mockReader := mockReader{}
+	mrs := spanstoremetrics.NewReaderDecoratorWithOptions(&mockReader, mf, spanstoremetrics.Options{
+		Logger: zap.New(core),
+	})
+	mockReader.On("GetServices", context.Background()).Return([]string{}, nil)
+
+	mrs.GetServices(context.Background())
+
+	counters, _ := mf.Snapshot()
+	assert.Equal(t, int64(0), counters["slow_queries|operation=get_services"])
+	assert.Equal(t, 0, observed.Len())
+}
+
+func TestReaderDecorator_SlowQueryLogsRedactedRecord(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	core, observed := observer.New(zap.WarnLevel)
+	mockReader := mockReader{}
+	mrs := spanstoremetrics.NewReaderDecoratorWithOptions(&mockReader, mf, spanstoremetrics.Options{
+		SlowQueryThreshold: time.Nanosecond, // every call takes at least this long
+		Logger:             zap.New(core),
+	})
+	query := &spanstore.TraceQueryParameters{
+		ServiceName:   "svc",
+		OperationName: "op",
+		Tags:          map[string]string{"http.status_code": "500", "secret": "do-not-log"},
+		StartTimeMin:  time.Unix(0, 0),
+		StartTimeMax:  time.Unix(0, 0).Add(time.Hour),
+		NumTraces:     20,
+	}
+	mockReader.On("FindTraces", context.Background(), query).Return([]*model.Trace{}, nil)
+
+	mrs.FindTraces(context.Background(), query)
+
+	counters, _ := mf.Snapshot()
+	assert.Equal(t, int64(1), counters["slow_queries|operation=find_traces"])
+	require.Equal(t, 1, observed.Len())
+	entry := observed.All()[0]
+	fields := entry.ContextMap()
+	assert.Equal(t, "svc", fields["service"])
+	assert.EqualValues(t, 2, fields["num_tags"])
+	assert.ElementsMatch(t, []string{"http.status_code", "secret"}, fields["tag_keys"])
+	assert.NotContains(t, entry.Message, "do-not-log")
+	for _, f := range entry.Context {
+		assert.NotContains(t, f.String, "do-not-log")
+	}
+}
+
+func TestReaderDecorator_SlowQueryAnnotatesRecordingSpan(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	tracer := tp.Tracer("test")
+	mockReader := mockReader{}
+	mrs := spanstoremetrics.NewReaderDecoratorWithOptions(&mockReader, mf, spanstoremetrics.Options{
+		SlowQueryThreshold: time.Nanosecond,
+		Tracer:             tracer,
+	})
+	ctx, span := tracer.Start(context.Background(), "find_traces")
+	mockReader.On("FindTraces", ctx, &spanstore.TraceQueryParameters{}).Return([]*model.Trace{}, nil)
+
+	mrs.FindTraces(ctx, &spanstore.TraceQueryParameters{})
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	found := false
+	for _, kv := range spans[0].Attributes() {
+		if string(kv.Key) == "operation" {
+			found = true
+			assert.Equal(t, "find_traces", kv.Value.AsString())
+		}
+	}
+	assert.True(t, found, "expected the slow-query attributes on a span that was already being recorded")
+}
+
+// TestReaderDecorator_SlowQueryNoopsOnNonRecordingSpan documents that a span
+// sampled out by the head sampler cannot be retroactively kept: OTel's
+// SetAttributes is a no-op once a span isn't recording, so nothing should be
+// (and nothing can be) attached. Operators rely on the slow_queries counter
+// and Logger for this case instead.
+func TestReaderDecorator_SlowQueryNoopsOnNonRecordingSpan(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithSampler(sdktrace.NeverSample()))
+	tracer := tp.Tracer("test")
+	mockReader := mockReader{}
+	mrs := spanstoremetrics.NewReaderDecoratorWithOptions(&mockReader, mf, spanstoremetrics.Options{
+		SlowQueryThreshold: time.Nanosecond,
+		Tracer:             tracer,
+	})
+	ctx, span := tracer.Start(context.Background(), "find_traces")
+	require.False(t, span.IsRecording())
+	mockReader.On("FindTraces", ctx, &spanstore.TraceQueryParameters{}).Return([]*model.Trace{}, nil)
+
+	mrs.FindTraces(ctx, &spanstore.TraceQueryParameters{})
+	span.End()
+
+	counters, _ := mf.Snapshot()
+	assert.Equal(t, int64(1), counters["slow_queries|operation=find_traces"], "counter still fires even though the span can't be annotated")
+}
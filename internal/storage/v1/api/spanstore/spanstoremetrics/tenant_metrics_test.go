@@ -0,0 +1,63 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstoremetrics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/internal/metricstest"
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore/spanstoremetrics"
+	"github.com/jaegertracing/jaeger/internal/tenancy"
+)
+
+func TestReaderDecorator_WithoutTenantContextIsBackwardCompatible(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	mockReader := mockReader{}
+	mrs := spanstoremetrics.NewReaderDecoratorWithOptions(&mockReader, mf, spanstoremetrics.Options{
+		TenantLabel: spanstoremetrics.TenantLabelOptions{Enabled: true, MaxCardinality: 2},
+	})
+	mockReader.On("GetServices", context.Background()).Return([]string{}, nil)
+
+	mrs.GetServices(context.Background())
+
+	counters, _ := mf.Snapshot()
+	assert.Equal(t, int64(1), counters["requests|operation=get_services|result=ok"])
+}
+
+func TestReaderDecorator_TagsMetricsWithTenant(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	mockReader := mockReader{}
+	mrs := spanstoremetrics.NewReaderDecoratorWithOptions(&mockReader, mf, spanstoremetrics.Options{
+		TenantLabel: spanstoremetrics.TenantLabelOptions{Enabled: true, MaxCardinality: 2},
+	})
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	mockReader.On("GetServices", ctx).Return([]string{}, nil)
+
+	mrs.GetServices(ctx)
+
+	counters, _ := mf.Snapshot()
+	assert.Equal(t, int64(1), counters["requests|operation=get_services|result=ok|tenant=acme"])
+}
+
+func TestReaderDecorator_CollapsesOverflowTenants(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+	mockReader := mockReader{}
+	mrs := spanstoremetrics.NewReaderDecoratorWithOptions(&mockReader, mf, spanstoremetrics.Options{
+		TenantLabel: spanstoremetrics.TenantLabelOptions{Enabled: true, MaxCardinality: 1},
+	})
+	ctxA := tenancy.WithTenant(context.Background(), "a")
+	ctxB := tenancy.WithTenant(context.Background(), "b")
+	mockReader.On("GetServices", ctxA).Return([]string{}, nil)
+	mockReader.On("GetServices", ctxB).Return([]string{}, nil)
+
+	mrs.GetServices(ctxA)
+	mrs.GetServices(ctxB)
+
+	counters, _ := mf.Snapshot()
+	assert.Equal(t, int64(1), counters["requests|operation=get_services|result=ok|tenant=a"])
+	assert.Equal(t, int64(1), counters["requests|operation=get_services|result=ok|tenant=_other_"])
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package spanstoretracing decorates spanstore.Reader/Writer implementations
+// with OpenTelemetry spans, so that Jaeger's own storage calls can be traced
+// and correlated with the spans emitted by the underlying database driver
+// (Cassandra, Elasticsearch, Badger, ...). It is the tracing counterpart of
+// spanstoremetrics, which records counters/latency instead.
+package spanstoretracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore"
+)
+
+// ReaderDecorator wraps a spanstore.Reader so that every call opens an
+// OpenTelemetry span named after the operation, propagating the caller's
+// context so the underlying storage driver's spans nest under it.
+type ReaderDecorator struct {
+	spanReader spanstore.Reader
+	tracer     trace.Tracer
+}
+
+// NewReaderDecorator returns a new ReaderDecorator using the given tracer.
+func NewReaderDecorator(reader spanstore.Reader, tracer trace.Tracer) *ReaderDecorator {
+	return &ReaderDecorator{
+		spanReader: reader,
+		tracer:     tracer,
+	}
+}
+
+func (r *ReaderDecorator) GetTrace(ctx context.Context, query spanstore.GetTraceParameters) (*model.Trace, error) {
+	ctx, span := r.tracer.Start(ctx, "get_trace", trace.WithAttributes(
+		attribute.String("trace_id", query.TraceID.String()),
+	))
+	defer span.End()
+	trace_, err := r.spanReader.GetTrace(ctx, query)
+	if err == nil {
+		span.SetAttributes(attribute.Int("num_results", traceLen(trace_)))
+	}
+	recordError(span, err)
+	return trace_, err
+}
+
+func (r *ReaderDecorator) GetServices(ctx context.Context) ([]string, error) {
+	ctx, span := r.tracer.Start(ctx, "get_services")
+	defer span.End()
+	services, err := r.spanReader.GetServices(ctx)
+	span.SetAttributes(attribute.Int("num_results", len(services)))
+	recordError(span, err)
+	return services, err
+}
+
+func (r *ReaderDecorator) GetOperations(ctx context.Context, query spanstore.OperationQueryParameters) ([]spanstore.Operation, error) {
+	ctx, span := r.tracer.Start(ctx, "get_operations", trace.WithAttributes(
+		attribute.String("service", query.ServiceName),
+		attribute.String("span_kind", query.SpanKind),
+	))
+	defer span.End()
+	operations, err := r.spanReader.GetOperations(ctx, query)
+	span.SetAttributes(attribute.Int("num_results", len(operations)))
+	recordError(span, err)
+	return operations, err
+}
+
+func (r *ReaderDecorator) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	ctx, span := r.tracer.Start(ctx, "find_traces", trace.WithAttributes(queryAttributes(query)...))
+	defer span.End()
+	traces, err := r.spanReader.FindTraces(ctx, query)
+	span.SetAttributes(attribute.Int("num_results", len(traces)))
+	recordError(span, err)
+	return traces, err
+}
+
+func (r *ReaderDecorator) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	ctx, span := r.tracer.Start(ctx, "find_trace_ids", trace.WithAttributes(queryAttributes(query)...))
+	defer span.End()
+	traceIDs, err := r.spanReader.FindTraceIDs(ctx, query)
+	span.SetAttributes(attribute.Int("num_results", len(traceIDs)))
+	recordError(span, err)
+	return traceIDs, err
+}
+
+// queryAttributes derives span attributes from a trace query so slow
+// FindTraces calls can be correlated with the parameters that caused them.
+func queryAttributes(query *spanstore.TraceQueryParameters) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("service", query.ServiceName),
+		attribute.String("operation", query.OperationName),
+		attribute.Int("num_tags", len(query.Tags)),
+		attribute.String("start_time_min", query.StartTimeMin.String()),
+		attribute.String("start_time_max", query.StartTimeMax.String()),
+		attribute.Int("num_traces", query.NumTraces),
+	}
+}
+
+func traceLen(t *model.Trace) int {
+	if t == nil {
+		return 0
+	}
+	return len(t.Spans)
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+var _ spanstore.Reader = (*ReaderDecorator)(nil)
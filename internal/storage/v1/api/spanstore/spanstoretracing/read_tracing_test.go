@@ -0,0 +1,89 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstoretracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore"
+)
+
+type mockReader struct {
+	trace *model.Trace
+	err   error
+	ctx   context.Context // context received by the last GetTrace call
+}
+
+func (m *mockReader) GetTrace(ctx context.Context, _ spanstore.GetTraceParameters) (*model.Trace, error) {
+	m.ctx = ctx
+	return m.trace, m.err
+}
+
+func (m *mockReader) GetServices(context.Context) ([]string, error) { return nil, m.err }
+
+func (m *mockReader) GetOperations(context.Context, spanstore.OperationQueryParameters) ([]spanstore.Operation, error) {
+	return nil, m.err
+}
+
+func (m *mockReader) FindTraces(context.Context, *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	return nil, m.err
+}
+
+func (m *mockReader) FindTraceIDs(context.Context, *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	return nil, m.err
+}
+
+func newTestTracerProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return tp, recorder
+}
+
+func TestReaderDecorator_GetTrace_PropagatesContextAndRecordsSpan(t *testing.T) {
+	tp, recorder := newTestTracerProvider()
+	inner := &mockReader{trace: &model.Trace{}}
+	reader := NewReaderDecorator(inner, tp.Tracer("test"))
+
+	_, err := reader.GetTrace(context.Background(), spanstore.GetTraceParameters{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if got := spans[0].Name(); got != "get_trace" {
+		t.Errorf("expected span name get_trace, got %s", got)
+	}
+	if got := trace.SpanContextFromContext(inner.ctx).SpanID(); got != spans[0].SpanContext().SpanID() {
+		t.Errorf("expected the wrapped reader to receive the get_trace span in its context")
+	}
+}
+
+func TestReaderDecorator_GetServices_RecordsErrorOnFailure(t *testing.T) {
+	tp, recorder := newTestTracerProvider()
+	wantErr := errors.New("boom")
+	reader := NewReaderDecorator(&mockReader{err: wantErr}, tp.Tracer("test"))
+
+	_, err := reader.GetServices(context.Background())
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if len(spans[0].Events()) == 0 {
+		t.Errorf("expected error to be recorded as a span event")
+	}
+}
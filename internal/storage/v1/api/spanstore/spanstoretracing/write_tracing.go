@@ -0,0 +1,43 @@
+// Copyright (c) 2025 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstoretracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/jaegertracing/jaeger/internal/storage/v1/api/spanstore"
+)
+
+// WriterDecorator wraps a spanstore.Writer so that every WriteSpan call opens
+// an OpenTelemetry span, propagating the caller's context so the underlying
+// storage driver's spans nest under it.
+type WriterDecorator struct {
+	spanWriter spanstore.Writer
+	tracer     trace.Tracer
+}
+
+// NewWriterDecorator returns a new WriterDecorator using the given tracer.
+func NewWriterDecorator(writer spanstore.Writer, tracer trace.Tracer) *WriterDecorator {
+	return &WriterDecorator{
+		spanWriter: writer,
+		tracer:     tracer,
+	}
+}
+
+func (w *WriterDecorator) WriteSpan(ctx context.Context, span *model.Span) error {
+	ctx, otelSpan := w.tracer.Start(ctx, "write_span", trace.WithAttributes(
+		attribute.String("service", span.Process.ServiceName),
+		attribute.String("operation", span.OperationName),
+	))
+	defer otelSpan.End()
+	err := w.spanWriter.WriteSpan(ctx, span)
+	recordError(otelSpan, err)
+	return err
+}
+
+var _ spanstore.Writer = (*WriterDecorator)(nil)
@@ -0,0 +1,15 @@
+// Copyright (c) 2017 The Jaeger Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package spanstore
+
+import (
+	"context"
+
+	"github.com/jaegertracing/jaeger-idl/model/v1"
+)
+
+// Writer writes spans to storage.
+type Writer interface {
+	WriteSpan(ctx context.Context, span *model.Span) error
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/olivere/elastic/v7"
@@ -24,6 +25,12 @@ const (
 	samplingIndexBaseName = "jaeger-sampling"
 	throughputType        = "throughput-sampling"
 	probabilitiesType     = "probabilities-sampling"
+
+	// defaultWriteTimeout bounds retries for InsertThroughput and
+	// InsertProbabilitiesAndQPS, which block on the outcome of their bulk
+	// writes. Applied only when Params.BulkWriterOptions.MaxElapsedTime is
+	// left at its zero value.
+	defaultWriteTimeout = time.Minute
 )
 
 type SamplingStore struct {
@@ -35,6 +42,8 @@ type SamplingStore struct {
 	indexRolloverFrequency time.Duration
 	lookback               time.Duration
 	useDataStream          bool
+	bulkWriter             *es.BulkWriter
+	indexResolver          config.IndexResolver
 }
 
 type Params struct {
@@ -46,6 +55,30 @@ type Params struct {
 	Lookback               time.Duration
 	MaxDocCount            int
 	UseDataStream          bool
+	// BulkWriterOptions configures the bulk flush/retry behavior used for
+	// throughput and probability writes. Zero value falls back to sane defaults.
+	// InsertThroughput and InsertProbabilitiesAndQPS block until their documents
+	// are flushed, so unlike es.BulkWriter itself (where a zero MaxElapsedTime
+	// means retry forever), a zero MaxElapsedTime here defaults to
+	// defaultWriteTimeout instead of leaving these calls able to block
+	// indefinitely on an outage. Set MaxElapsedTime explicitly (to a very long
+	// duration) if truly-unbounded retries are wanted.
+	BulkWriterOptions es.BulkWriterOptions
+	// OnBulkFailure, if set, is called with documents that exhausted retries
+	// so operators can route them to a DLQ. Defaults to logging via Logger.
+	OnBulkFailure func(items []es.BulkItem, err error)
+	// IndexResolver, if set, is used to resolve read indices via the
+	// Elasticsearch _resolve/index API instead of walking dates. Nil falls
+	// back to the legacy date-walk (e.g. talking to a pre-7.9 cluster).
+	//
+	// If IndexResolver is nil but RawClient is set, NewSamplingStore defaults
+	// to a config.ESIndexResolver built from RawClient, so passing RawClient
+	// is normally all a caller needs to do to get _resolve/index-backed reads.
+	IndexResolver config.IndexResolver
+	// RawClient gives NewSamplingStore access to the underlying olivere/elastic
+	// client so it can default IndexResolver to a real config.ESIndexResolver.
+	// Unused if IndexResolver is set explicitly.
+	RawClient func() *elastic.Client
 }
 
 func NewSamplingStore(p Params) *SamplingStore {
@@ -57,7 +90,11 @@ func NewSamplingStore(p Params) *SamplingStore {
 	if !p.UseDataStream {
 		prefix += config.IndexPrefixSeparator
 	}
-	return &SamplingStore{
+	indexResolver := p.IndexResolver
+	if indexResolver == nil && p.RawClient != nil {
+		indexResolver = config.NewESIndexResolver(p.RawClient)
+	}
+	s := &SamplingStore{
 		client:                 p.Client,
 		logger:                 p.Logger,
 		samplingIndexPrefix:    prefix,
@@ -66,25 +103,86 @@ func NewSamplingStore(p Params) *SamplingStore {
 		indexRolloverFrequency: p.IndexRolloverFrequency,
 		lookback:               p.Lookback,
 		useDataStream:          p.UseDataStream,
+		indexResolver:          indexResolver,
+	}
+	onFailure := p.OnBulkFailure
+	if onFailure == nil {
+		onFailure = s.logBulkFailure
+	}
+	opts := p.BulkWriterOptions
+	if opts.MaxElapsedTime == 0 {
+		opts.MaxElapsedTime = defaultWriteTimeout
+	}
+	opts.OnFailure = onFailure
+	s.bulkWriter = es.NewBulkWriter(opts, s.flushBulkItems)
+	return s
+}
+
+func (s *SamplingStore) logBulkFailure(items []es.BulkItem, err error) {
+	s.logger.Error("failed to write sampling documents after exhausting retries", zap.Int("count", len(items)), zap.Error(err))
+}
+
+// flushBulkItems sends a batch of queued throughput/probability documents to
+// Elasticsearch and reports which of them the backend rejected.
+func (s *SamplingStore) flushBulkItems(ctx context.Context, items []es.BulkItem) ([]es.BulkItemError, error) {
+	bulk := s.client().Bulk()
+	for _, item := range items {
+		req := elastic.NewBulkIndexRequest().Index(item.Index).Type(item.Type).Doc(item.Body)
+		if item.OpType != "" {
+			req = req.OpType(item.OpType)
+		}
+		bulk = bulk.Add(req)
+	}
+	resp, err := bulk.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bulk request failed: %w", err)
 	}
+	var failed []es.BulkItemError
+	for i, itemResult := range resp.Items {
+		for _, result := range itemResult {
+			if result.Error == nil {
+				continue
+			}
+			failed = append(failed, es.BulkItemError{
+				Item:      items[i],
+				Err:       errors.New(result.Error.Reason),
+				Retryable: result.Status == http.StatusTooManyRequests || result.Status == http.StatusServiceUnavailable,
+			})
+		}
+	}
+	return failed, nil
+}
+
+func (s *SamplingStore) opType(ts time.Time) string {
+	if s.useDataStream || s.client().GetVersion() >= 8 {
+		return "create"
+	}
+	return ""
 }
 
 func (s *SamplingStore) InsertThroughput(throughput []*model.Throughput) error {
 	ts := time.Now()
 	indexName := s.getWriteIndex(ts)
-	for _, eachThroughput := range dbmodel.FromThroughputs(throughput) {
-		il := s.client().Index().Index(indexName).Type(throughputType).
-			BodyJson(&dbmodel.TimeThroughput{
+	opType := s.opType(ts)
+	all := dbmodel.FromThroughputs(throughput)
+	results := make([]<-chan error, 0, len(all))
+	for _, eachThroughput := range all {
+		results = append(results, s.bulkWriter.Add(es.BulkItem{
+			Index:  indexName,
+			Type:   throughputType,
+			OpType: opType,
+			Body: &dbmodel.TimeThroughput{
 				Timestamp:  ts,
 				Throughput: eachThroughput,
-			})
-		opType := ""
-		if s.useDataStream || s.client().GetVersion() >= 8 {
-			opType = "create"
-		}
-		il.Add(opType)
+			},
+		}))
 	}
-	return nil
+	return es.Await(results...)
+}
+
+// Close flushes any queued documents and stops the underlying bulk writer.
+func (s *SamplingStore) Close() error {
+	return s.bulkWriter.Close()
 }
 
 func (s *SamplingStore) GetThroughput(start, end time.Time) ([]*model.Throughput, error) {
@@ -121,8 +219,7 @@ func (s *SamplingStore) InsertProbabilitiesAndQPS(_ string,
 		Probabilities: probabilities,
 		QPS:           qps,
 	}
-	s.writeProbabilitiesAndQPS(writeIndexName, ts, val)
-	return nil
+	return es.Await(s.writeProbabilitiesAndQPS(writeIndexName, ts, val))
 }
 
 func (s *SamplingStore) getWriteIndex(ts time.Time) string {
@@ -166,25 +263,35 @@ func (s *SamplingStore) GetLatestProbabilities() (model.ServiceOperationProbabil
 	return latestProbabilities.ProbabilitiesAndQPS.Probabilities, nil
 }
 
-func (s *SamplingStore) writeProbabilitiesAndQPS(indexName string, ts time.Time, pandqps dbmodel.ProbabilitiesAndQPS) {
-	il := s.client().Index().Index(indexName).Type(probabilitiesType).
-		BodyJson(&dbmodel.TimeProbabilitiesAndQPS{
+func (s *SamplingStore) writeProbabilitiesAndQPS(indexName string, ts time.Time, pandqps dbmodel.ProbabilitiesAndQPS) <-chan error {
+	return s.bulkWriter.Add(es.BulkItem{
+		Index:  indexName,
+		Type:   probabilitiesType,
+		OpType: s.opType(ts),
+		Body: &dbmodel.TimeProbabilitiesAndQPS{
 			Timestamp:           ts,
 			ProbabilitiesAndQPS: pandqps,
-		})
-	opType := ""
-	if s.useDataStream || s.client().GetVersion() >= 8 {
-		opType = "create"
-	}
-	il.Add(opType)
+		},
+	})
 }
 
 func (s *SamplingStore) getLatestIndices() ([]string, error) {
 	if s.useDataStream {
 		indices := []string{s.samplingIndexPrefix}
-		indices = append(indices, config.GetDataStreamLegacyWildcard(s.samplingIndexPrefix))
+		latest, err := config.ResolveLatestIndex(context.Background(), s.indexResolver, s.samplingIndexPrefix,
+			func() ([]string, error) { return config.GetDataStreamLegacyWildcard(s.samplingIndexPrefix), nil })
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, latest...)
 		return indices, nil
 	}
+	return config.ResolveLatestIndex(context.Background(), s.indexResolver, s.samplingIndexPrefix, s.dateWalkLatestIndex)
+}
+
+// dateWalkLatestIndex is the legacy latest-index resolution, kept as a
+// fallback for Elasticsearch clusters older than 7.9 (no _resolve/index API).
+func (s *SamplingStore) dateWalkLatestIndex() ([]string, error) {
 	clientFn := s.client()
 	ctx := context.Background()
 	now := time.Now().UTC()
@@ -209,9 +316,17 @@ func (s *SamplingStore) getLatestIndices() ([]string, error) {
 func (s *SamplingStore) getReadIndices(startTime time.Time, endTime time.Time) []string {
 	if s.useDataStream {
 		indices := []string{s.samplingIndexPrefix}
-		indices = append(indices, config.GetDataStreamLegacyWildcard(s.samplingIndexPrefix))
+		indices = append(indices, config.ResolveIndices(context.Background(), s.indexResolver, s.samplingIndexPrefix, startTime, endTime,
+			func() []string { return config.GetDataStreamLegacyWildcard(s.samplingIndexPrefix) })...)
 		return indices
 	}
+	return config.ResolveIndices(context.Background(), s.indexResolver, s.samplingIndexPrefix, startTime, endTime,
+		func() []string { return s.dateWalkReadIndices(startTime, endTime) })
+}
+
+// dateWalkReadIndices is the legacy read-index resolution, kept as a
+// fallback for Elasticsearch clusters older than 7.9 (no _resolve/index API).
+func (s *SamplingStore) dateWalkReadIndices(startTime, endTime time.Time) []string {
 	var indices []string
 	firstIndex := config.IndexWithDate(s.samplingIndexPrefix, s.indexDateLayout, startTime)
 	currentIndex := config.IndexWithDate(s.samplingIndexPrefix, s.indexDateLayout, endTime)
@@ -6,7 +6,9 @@ package depstore
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/olivere/elastic/v7"
@@ -22,6 +24,11 @@ const (
 	// dependencyType is the documentation type for the dependencies
 	dependencyType          = "dependencies"
 	dependencyIndexBaseName = "jaeger-dependencies"
+
+	// defaultWriteTimeout bounds retries for WriteDependencies, which blocks
+	// on the outcome of its bulk write. Applied only when
+	// Params.BulkWriterOptions.MaxElapsedTime is left at its zero value.
+	defaultWriteTimeout = time.Minute
 )
 
 // CoreDependencyStore is a DB Level abstraction which directly read/write dependencies into ElasticSearch
@@ -43,6 +50,8 @@ type DependencyStore struct {
 	indexRolloverFrequency time.Duration
 	maxDocCount            int
 	useReadWriteAliases    bool
+	bulkWriter             *es.BulkWriter
+	indexResolver          config.IndexResolver
 }
 
 // Params holds the parameters for the DependencyStore
@@ -54,11 +63,38 @@ type Params struct {
 	IndexRolloverFrequency time.Duration
 	MaxDocCount            int
 	UseReadWriteAliases    bool
+	// BulkWriterOptions configures the bulk flush/retry behavior used for
+	// dependency writes. Zero value falls back to sane defaults. WriteDependencies
+	// blocks until its documents are flushed, so unlike es.BulkWriter itself
+	// (where a zero MaxElapsedTime means retry forever), a zero MaxElapsedTime
+	// here defaults to defaultWriteTimeout instead of leaving this call able
+	// to block indefinitely on an outage. Set MaxElapsedTime explicitly (to a
+	// very long duration) if truly-unbounded retries are wanted.
+	BulkWriterOptions es.BulkWriterOptions
+	// OnBulkFailure, if set, is called with documents that exhausted
+	// retries so operators can route them to a DLQ. Defaults to logging via Logger.
+	OnBulkFailure func(items []es.BulkItem, err error)
+	// IndexResolver, if set, is used to resolve read indices via the
+	// Elasticsearch _resolve/index API instead of walking dates. Nil falls
+	// back to the legacy date-walk (e.g. talking to a pre-7.9 cluster).
+	//
+	// If IndexResolver is nil but RawClient is set, NewDependencyStore defaults
+	// to a config.ESIndexResolver built from RawClient, so passing RawClient
+	// is normally all a caller needs to do to get _resolve/index-backed reads.
+	IndexResolver config.IndexResolver
+	// RawClient gives NewDependencyStore access to the underlying
+	// olivere/elastic client so it can default IndexResolver to a real
+	// config.ESIndexResolver. Unused if IndexResolver is set explicitly.
+	RawClient func() *elastic.Client
 }
 
 // NewDependencyStore returns a DependencyStore
 func NewDependencyStore(p Params) *DependencyStore {
-	return &DependencyStore{
+	indexResolver := p.IndexResolver
+	if indexResolver == nil && p.RawClient != nil {
+		indexResolver = config.NewESIndexResolver(p.RawClient)
+	}
+	s := &DependencyStore{
 		client:                 p.Client,
 		logger:                 p.Logger,
 		dependencyIndexPrefix:  p.IndexPrefix.Apply(dependencyIndexBaseName) + config.IndexPrefixSeparator,
@@ -66,7 +102,50 @@ func NewDependencyStore(p Params) *DependencyStore {
 		indexRolloverFrequency: p.IndexRolloverFrequency,
 		maxDocCount:            p.MaxDocCount,
 		useReadWriteAliases:    p.UseReadWriteAliases,
+		indexResolver:          indexResolver,
+	}
+	onFailure := p.OnBulkFailure
+	if onFailure == nil {
+		onFailure = s.logBulkFailure
+	}
+	opts := p.BulkWriterOptions
+	if opts.MaxElapsedTime == 0 {
+		opts.MaxElapsedTime = defaultWriteTimeout
+	}
+	opts.OnFailure = onFailure
+	s.bulkWriter = es.NewBulkWriter(opts, s.flushBulkItems)
+	return s
+}
+
+func (s *DependencyStore) logBulkFailure(items []es.BulkItem, err error) {
+	s.logger.Error("failed to write dependency documents after exhausting retries", zap.Int("count", len(items)), zap.Error(err))
+}
+
+// flushBulkItems sends a batch of queued dependency documents to
+// Elasticsearch and reports which of them the backend rejected.
+func (s *DependencyStore) flushBulkItems(ctx context.Context, items []es.BulkItem) ([]es.BulkItemError, error) {
+	bulk := s.client().Bulk()
+	for _, item := range items {
+		bulk = bulk.Add(elastic.NewBulkIndexRequest().Index(item.Index).Type(item.Type).Doc(item.Body))
 	}
+	resp, err := bulk.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bulk request failed: %w", err)
+	}
+	var failed []es.BulkItemError
+	for i, itemResult := range resp.Items {
+		for _, result := range itemResult {
+			if result.Error == nil {
+				continue
+			}
+			failed = append(failed, es.BulkItemError{
+				Item:      items[i],
+				Err:       errors.New(result.Error.Reason),
+				Retryable: result.Status == http.StatusTooManyRequests || result.Status == http.StatusServiceUnavailable,
+			})
+		}
+	}
+	return failed, nil
 }
 
 // WriteDependencies implements dependencyWriter
@@ -75,8 +154,12 @@ func (s *DependencyStore) WriteDependencies(ts time.Time, dependencies []dbmodel
 	if err := s.createIndex(indexName); err != nil {
 		return err
 	}
-	s.writeDependenciesToIndex(indexName, ts, dependencies)
-	return nil
+	return es.Await(s.writeDependenciesToIndex(indexName, ts, dependencies))
+}
+
+// Close flushes any queued documents and stops the underlying bulk writer.
+func (s *DependencyStore) Close() error {
+	return s.bulkWriter.Close()
 }
 
 // CreateTemplates creates index templates.
@@ -89,12 +172,15 @@ func (s *DependencyStore) CreateTemplates(dependenciesTemplate string) error {
 	return nil
 }
 
-func (s *DependencyStore) writeDependenciesToIndex(indexName string, ts time.Time, dependencies []dbmodel.DependencyLink) {
-	s.client().Index().Index(indexName).Type(dependencyType).
-		BodyJson(&dbmodel.TimeDependencies{
+func (s *DependencyStore) writeDependenciesToIndex(indexName string, ts time.Time, dependencies []dbmodel.DependencyLink) <-chan error {
+	return s.bulkWriter.Add(es.BulkItem{
+		Index: indexName,
+		Type:  dependencyType,
+		Body: &dbmodel.TimeDependencies{
 			Timestamp:    ts,
 			Dependencies: dependencies,
-		}).Add("")
+		},
+	})
 }
 
 func (s *DependencyStore) createIndex(indexName string) error {
@@ -146,6 +232,14 @@ func (s *DependencyStore) getReadIndices(endTs time.Time, lookback time.Duration
 	if s.useReadWriteAliases {
 		return []string{s.dependencyIndexPrefix + "read"}
 	}
+	startTs := endTs.Add(-lookback)
+	return config.ResolveIndices(context.Background(), s.indexResolver, s.dependencyIndexPrefix, startTs, endTs,
+		func() []string { return s.dateWalkReadIndices(endTs, lookback) })
+}
+
+// dateWalkReadIndices is the legacy read-index resolution, kept as a
+// fallback for Elasticsearch clusters older than 7.9 (no _resolve/index API).
+func (s *DependencyStore) dateWalkReadIndices(endTs time.Time, lookback time.Duration) []string {
 	var indices []string
 	firstIndex := config.IndexWithDate(s.dependencyIndexPrefix, s.indexDateLayout, endTs.Add(-lookback))
 	currentIndex := config.IndexWithDate(s.dependencyIndexPrefix, s.indexDateLayout, endTs)